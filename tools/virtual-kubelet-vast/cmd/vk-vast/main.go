@@ -10,6 +10,10 @@ import (
 	"syscall"
 
 	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast"
+	vastmetrics "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/metrics"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/nodepool"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/scheduler"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	logutil "github.com/virtual-kubelet/virtual-kubelet/log"
 	"github.com/virtual-kubelet/virtual-kubelet/node"
 	"github.com/virtual-kubelet/virtual-kubelet/node/api"
@@ -20,8 +24,12 @@ import (
 	"k8s.io/klog/v2"
 )
 
+const basePodRoutesPort = 10255
+
 func main() {
 	klog.InitFlags(nil)
+	readyTimeout := flag.Duration("vast-ready-timeout", vast.DefaultReadyTimeout,
+		"how long CreatePod waits for a vast.ozzu.io/wait-ready=true pod's instance to become healthy before failing it")
 	flag.Parse()
 
 	ctx := context.Background()
@@ -37,8 +45,6 @@ func main() {
 		cancel()
 	}()
 
-	// Config
-	nodeName := getEnvOrDefault("NODENAME", "vast-gpu-node-na-1")
 	apiKey := os.Getenv("VAST_API_KEY")
 	if apiKey == "" {
 		logutil.G(ctx).Fatal("VAST_API_KEY environment variable is required")
@@ -54,73 +60,150 @@ func main() {
 		logutil.G(ctx).WithError(err).Fatal("Failed to create Kubernetes client")
 	}
 
-	// Provider
-	provider, err := vast.NewVastProvider(ctx, apiKey, nodeName)
+	nodes := loadNodePool(ctx)
+
+	nodeControllers := make([]*node.NodeController, 0, len(nodes))
+	for i, n := range nodes {
+		provider, err := vast.NewVastProvider(ctx, apiKey, n.Name, clientset)
+		if err != nil {
+			logutil.G(ctx).WithError(err).Fatalf("Failed to initialize Vast.ai provider for node %s", n.Name)
+		}
+		provider.SetSchedulerConfig(schedulerConfigFromNode(n))
+		provider.SetReadyTimeout(*readyTimeout)
+
+		go provider.StartReconciler(ctx)
+		go provider.StartDisruptionController(ctx)
+		go provider.StartStateReconciler(ctx)
+
+		nodeController, err := startNode(ctx, provider, n, clientset, basePodRoutesPort+i)
+		if err != nil {
+			logutil.G(ctx).WithError(err).Fatalf("Failed to start node %s", n.Name)
+		}
+		nodeControllers = append(nodeControllers, nodeController)
+	}
+
+	for _, nc := range nodeControllers {
+		select {
+		case <-nc.Ready():
+			logutil.G(ctx).Info("NodeController ready")
+		case <-nc.Done():
+			if err := nc.Err(); err != nil {
+				logutil.G(ctx).WithError(err).Fatal("NodeController failed")
+			}
+			logutil.G(ctx).Info("NodeController stopped")
+			return
+		}
+	}
+
+	<-ctx.Done()
+	logutil.G(ctx).Info("Virtual Kubelet Vast.ai provider stopped")
+}
+
+// loadNodePool reads VAST_POOL_CONFIG if set, otherwise falls back to the single
+// RTX3060/north-america node this binary has always advertised.
+func loadNodePool(ctx context.Context) []nodepool.NodeConfig {
+	path := os.Getenv("VAST_POOL_CONFIG")
+	if path == "" {
+		return []nodepool.NodeConfig{defaultNodeConfig()}
+	}
+
+	pool, err := nodepool.Load(path)
 	if err != nil {
-		logutil.G(ctx).WithError(err).Fatal("Failed to initialize Vast.ai provider")
+		logutil.G(ctx).WithError(err).Fatal("Failed to load VAST_POOL_CONFIG")
+	}
+	return pool.Nodes
+}
+
+func defaultNodeConfig() nodepool.NodeConfig {
+	return nodepool.NodeConfig{
+		Name: getEnvOrDefault("NODENAME", "vast-gpu-node-na-1"),
+		Labels: map[string]string{
+			"provider":                         "vast.ai",
+			"gpu.nvidia.com/class":             "RTX3060",
+			"node.kubernetes.io/instance-type": "vast.gpu",
+			"region":                           "north-america",
+			"kubernetes.io/arch":               "amd64",
+			"kubernetes.io/os":                 "linux",
+		},
+		Taints: []corev1.Taint{
+			{Key: "vast.ai/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "virtual-kubelet.io/provider", Value: "vast", Effect: corev1.TaintEffectNoSchedule},
+		},
+		GPUType:             "RTX_3060",
+		MinGPUMemoryGB:      12,
+		MaxPricePerHour:     0.50,
+		MinReliability:      0.95,
+		MinDownloadMbps:     100,
+		MinUploadMbps:       100,
+		VerifiedOnly:        true,
+		PreferredRegions:    []string{"US", "CA", "MX"},
+		FallbackRegions:     []string{"EU"},
+		BlockedRegions:      []string{"RU", "CN", "KP"},
+		MaxLatencyMS:        50,
+		LatencyCheckEnabled: true,
+		MinScoreThreshold:   0.3,
 	}
+}
 
-	// Build kube Node object for VK v1.11
+func schedulerConfigFromNode(n nodepool.NodeConfig) *scheduler.SchedulerConfig {
+	return &scheduler.SchedulerConfig{
+		GPUType:             n.GPUType,
+		MinGPUMemoryGB:      n.MinGPUMemoryGB,
+		MaxPricePerHour:     n.MaxPricePerHour,
+		MinReliability:      n.MinReliability,
+		MinDownloadMbps:     n.MinDownloadMbps,
+		MinUploadMbps:       n.MinUploadMbps,
+		VerifiedOnly:        n.VerifiedOnly,
+		PreferredRegions:    n.PreferredRegions,
+		FallbackRegions:     n.FallbackRegions,
+		BlockedRegions:      n.BlockedRegions,
+		MaxLatencyMS:        n.MaxLatencyMS,
+		LatencyCheckEnabled: n.LatencyCheckEnabled,
+		MinScoreThreshold:   n.MinScoreThreshold,
+	}
+}
+
+// startNode builds the kube Node object, node controller, and HTTP routes for a single
+// virtual node and starts it running in the background.
+func startNode(ctx context.Context, provider *vast.VastProvider, n nodepool.NodeConfig, clientset kubernetes.Interface, podRoutesPort int) (*node.NodeController, error) {
 	kubeNode := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: nodeName,
-			Labels: map[string]string{
-				"provider":                      "vast.ai",
-				"gpu.nvidia.com/class":         "RTX3060",
-				"node.kubernetes.io/instance-type": "vast.gpu",
-				"region":                       "north-america",
-				"kubernetes.io/arch":           "amd64",
-				"kubernetes.io/os":             "linux",
-			},
+			Name:   n.Name,
+			Labels: n.Labels,
 		},
 		Spec: corev1.NodeSpec{
-			Taints: []corev1.Taint{
-				{Key: "vast.ai/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
-				{Key: "virtual-kubelet.io/provider", Value: "vast", Effect: corev1.TaintEffectNoSchedule},
-			},
+			Taints: n.Taints,
 		},
 	}
 
 	nodesClient := clientset.CoreV1().Nodes()
 	nodeController, err := node.NewNodeController(provider, kubeNode, nodesClient)
 	if err != nil {
-		logutil.G(ctx).WithError(err).Fatal("Failed to create node controller")
+		return nil, fmt.Errorf("failed to create node controller: %w", err)
 	}
 
-	// HTTP server for VK routes
 	mux := http.NewServeMux()
 	api.AttachPodRoutes(provider, mux)
 	api.AttachMetricsRoutes(ctx, nodeController, mux, "")
+	mux.Handle("/metrics/vast", promhttp.Handler())
+	mux.Handle("/debug/scheduling", vastmetrics.DebugSchedulingHandler())
 
 	go func() {
-		srv := &http.Server{Addr: ":10255", Handler: mux}
+		srv := &http.Server{Addr: fmt.Sprintf(":%d", podRoutesPort), Handler: mux}
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logutil.G(ctx).WithError(err).Error("HTTP server error")
+			logutil.G(ctx).WithError(err).Errorf("HTTP server error for node %s", n.Name)
 		}
 	}()
 
-	logutil.G(ctx).Info(fmt.Sprintf("Starting Virtual Kubelet Vast.ai provider for node: %s", nodeName))
+	logutil.G(ctx).Info(fmt.Sprintf("Starting Virtual Kubelet Vast.ai provider for node: %s", n.Name))
 
-	// Run node controller
 	go func() {
 		if err := nodeController.Run(ctx); err != nil {
-			logutil.G(ctx).WithError(err).Error("NodeController exited with error")
+			logutil.G(ctx).WithError(err).Errorf("NodeController for node %s exited with error", n.Name)
 		}
 	}()
 
-	select {
-	case <-nodeController.Ready():
-		logutil.G(ctx).Info("NodeController ready")
-	case <-nodeController.Done():
-		if err := nodeController.Err(); err != nil {
-			logutil.G(ctx).WithError(err).Fatal("NodeController failed")
-		}
-		logutil.G(ctx).Info("NodeController stopped")
-		return
-	}
-
-	<-ctx.Done()
-	logutil.G(ctx).Info("Virtual Kubelet Vast.ai provider stopped")
+	return nodeController, nil
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
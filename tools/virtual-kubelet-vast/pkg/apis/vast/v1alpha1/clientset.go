@@ -0,0 +1,86 @@
+package v1alpha1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// Clientset is a minimal typed client for the VastOfferTemplate CRD. Real
+// client-gen output would generate a versioned.Interface tree under
+// pkg/generated/clientset/versioned with one package per API group; this tree
+// has no codegen toolchain wired up, so this single hand-written client
+// covers the one CRD that exists today, built the same way
+// (rest.RESTClientFor + a scheme-aware NegotiatedSerializer) client-gen's
+// generated clients are.
+type Clientset struct {
+	restClient rest.Interface
+}
+
+// NewForConfig builds a Clientset against the VastOfferTemplate CRD's group
+// version, registering its types onto client-go's default scheme first so the
+// REST client can encode/decode them.
+func NewForConfig(config *rest.Config) (*Clientset, error) {
+	if err := AddToScheme(scheme.Scheme); err != nil {
+		return nil, err
+	}
+
+	restConfig := *config
+	restConfig.ContentConfig.GroupVersion = &SchemeGroupVersion
+	restConfig.APIPath = "/apis"
+	restConfig.NegotiatedSerializer = serializer.NewCodecFactory(scheme.Scheme).WithoutConversion()
+
+	restClient, err := rest.RESTClientFor(&restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Clientset{restClient: restClient}, nil
+}
+
+// VastOfferTemplates returns the client for VastOfferTemplate resources in namespace.
+func (c *Clientset) VastOfferTemplates(namespace string) VastOfferTemplateInterface {
+	return &vastOfferTemplates{client: c.restClient, ns: namespace}
+}
+
+// VastOfferTemplateInterface is the subset of typed operations the scheduler
+// and its resolver need; it mirrors client-gen's generated interface shape.
+type VastOfferTemplateInterface interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*VastOfferTemplate, error)
+	UpdateStatus(ctx context.Context, template *VastOfferTemplate, opts metav1.UpdateOptions) (*VastOfferTemplate, error)
+}
+
+type vastOfferTemplates struct {
+	client rest.Interface
+	ns     string
+}
+
+const vastOfferTemplateResource = "vastoffertemplates"
+
+func (c *vastOfferTemplates) Get(ctx context.Context, name string, opts metav1.GetOptions) (*VastOfferTemplate, error) {
+	result := &VastOfferTemplate{}
+	err := c.client.Get().
+		Namespace(c.ns).
+		Resource(vastOfferTemplateResource).
+		Name(name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *vastOfferTemplates) UpdateStatus(ctx context.Context, template *VastOfferTemplate, opts metav1.UpdateOptions) (*VastOfferTemplate, error) {
+	result := &VastOfferTemplate{}
+	err := c.client.Put().
+		Namespace(c.ns).
+		Resource(vastOfferTemplateResource).
+		Name(template.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(template).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
@@ -0,0 +1,71 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group/version this package's types register under.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+// SchemeBuilder collects this package's AddToScheme functions, the same
+// pattern client-gen output uses so hand-written and generated types register
+// the same way.
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&VastOfferTemplate{},
+		&VastOfferTemplateList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// DeepCopyObject implements runtime.Object. Hand-written rather than
+// deepcopy-gen output, since this tree has no codegen toolchain wired up; it
+// follows the same shape deepcopy-gen would produce.
+func (in *VastOfferTemplate) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(VastOfferTemplate)
+	out.TypeMeta = in.TypeMeta
+	out.ObjectMeta = *in.ObjectMeta.DeepCopy()
+	out.Spec = in.Spec
+	if in.Spec.Regions != nil {
+		out.Spec.Regions = append([]string(nil), in.Spec.Regions...)
+	}
+	out.Status = in.Status
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *VastOfferTemplateList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(VastOfferTemplateList)
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		out.Items = make([]VastOfferTemplate, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return out
+}
+
+// DeepCopyInto copies in into out, overwriting it.
+func (in *VastOfferTemplate) DeepCopyInto(out *VastOfferTemplate) {
+	*out = *in
+	out.ObjectMeta = *in.ObjectMeta.DeepCopy()
+	if in.Spec.Regions != nil {
+		out.Spec.Regions = append([]string(nil), in.Spec.Regions...)
+	}
+}
@@ -0,0 +1,87 @@
+// Package v1alpha1 defines the VastOfferTemplate custom resource, which lets
+// users declare a reusable Vast.ai offer-selection policy once and reference
+// it from a Pod via the "vast.ozzu.io/offer-template" annotation instead of
+// repeating search criteria inline.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GroupName is the API group VastOfferTemplate is registered under.
+const GroupName = "vast.ozzu.io"
+
+// OfferTemplateAnnotation is the Pod annotation naming the VastOfferTemplate
+// (in the pod's own namespace) the scheduler should resolve offer-selection
+// criteria from.
+const OfferTemplateAnnotation = "vast.ozzu.io/offer-template"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VastOfferTemplate declares a reusable Vast.ai offer-selection policy.
+type VastOfferTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VastOfferTemplateSpec   `json:"spec"`
+	Status VastOfferTemplateStatus `json:"status,omitempty"`
+}
+
+// VastOfferTemplateSpec is the user-declared offer-selection policy.
+type VastOfferTemplateSpec struct {
+	// GPUModelGlob matches InstanceOffer.GPUName using shell-style globbing
+	// (path.Match semantics), e.g. "RTX_4090*" or "*A100*". Empty matches any.
+	GPUModelGlob string `json:"gpuModelGlob,omitempty"`
+
+	// MinVRAMGB is the minimum GPU memory, in GB, an offer must report.
+	MinVRAMGB int `json:"minVRAMGB,omitempty"`
+
+	// MaxPricePerHour is the highest $/hr an offer may cost.
+	MaxPricePerHour float64 `json:"maxPricePerHour,omitempty"`
+
+	// MinDLPerf is the lowest Vast.ai DLPerf score an offer may report.
+	MinDLPerf float64 `json:"minDLPerf,omitempty"`
+
+	// Regions lists acceptable geolocation prefixes (e.g. "US", "US-CA"); an
+	// offer must match at least one entry if the list is non-empty.
+	Regions []string `json:"regions,omitempty"`
+
+	// MinReliability is the lowest Vast.ai reliability score (0-1) an offer
+	// may report.
+	MinReliability float64 `json:"minReliability,omitempty"`
+
+	// MinDiskGB is the minimum disk space, in GB, an offer must report.
+	MinDiskGB int `json:"minDiskGB,omitempty"`
+}
+
+// VastOfferTemplateStatus records the outcome of the template's most recent
+// successful match, so operators can inspect why (or whether) it's landing
+// pods without having to correlate scheduler logs by hand.
+type VastOfferTemplateStatus struct {
+	// LastMatchedOfferID is the Vast.ai offer ID last launched through this
+	// template.
+	LastMatchedOfferID int `json:"lastMatchedOfferID,omitempty"`
+
+	// LastMatchedPricePerHour is that offer's $/hr at launch time.
+	LastMatchedPricePerHour float64 `json:"lastMatchedPricePerHour,omitempty"`
+
+	// LastMatchedGPUModel is that offer's reported GPU model.
+	LastMatchedGPUModel string `json:"lastMatchedGPUModel,omitempty"`
+
+	// LastMatchedPod is the namespaced name ("namespace/name") of the pod the
+	// match was made for.
+	LastMatchedPod string `json:"lastMatchedPod,omitempty"`
+
+	// LastMatchTime is when LastMatchedOfferID was recorded.
+	LastMatchTime metav1.Time `json:"lastMatchTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VastOfferTemplateList is a list of VastOfferTemplate resources.
+type VastOfferTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []VastOfferTemplate `json:"items"`
+}
@@ -9,10 +9,16 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api/sshexec"
 )
 
 const (
@@ -21,13 +27,20 @@ const (
 )
 
 type VastClient struct {
-	apiKey     string
-	httpClient *http.Client
-	baseURL    string
+	apiKey      string
+	httpClient  *http.Client
+	baseURL     string
+	kubeClient  kubernetes.Interface // used to resolve ConfigMap/Secret refs and imagePullSecrets
+	rateLimiter *rate.Limiter        // gates outbound requests, see ratelimit.go
+
+	sshPoolOnce sync.Once
+	sshPoolVal  *sshexec.Pool
+	sshPoolErr  error
 }
 
-// NewVastClient creates a new Vast.ai API client
-func NewVastClient(apiKey string) (*VastClient, error) {
+// NewVastClient creates a new Vast.ai API client. kubeClient may be nil, in which case
+// pods that reference ConfigMap/Secret env vars will fail to translate.
+func NewVastClient(apiKey string, kubeClient kubernetes.Interface) (*VastClient, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
@@ -37,7 +50,9 @@ func NewVastClient(apiKey string) (*VastClient, error) {
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
-		baseURL: VastAPIBaseURL,
+		baseURL:     VastAPIBaseURL,
+		kubeClient:  kubeClient,
+		rateLimiter: rate.NewLimiter(rate.Limit(DefaultQPS), DefaultBurst),
 	}, nil
 }
 
@@ -49,7 +64,7 @@ func (c *VastClient) TestConnection(ctx context.Context) error {
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return fmt.Errorf("HTTP request failed: %w", err)
 	}
@@ -77,6 +92,12 @@ func (c *VastClient) SearchInstances(ctx context.Context, criteria SearchCriteri
 		"inet_down_gte":   strconv.Itoa(criteria.MinDownloadMbps),
 		"inet_up_gte":     strconv.Itoa(criteria.MinUploadMbps),
 	}
+	if criteria.MinDLPerf > 0 {
+		params["dlperf_gte"] = fmt.Sprintf("%.2f", criteria.MinDLPerf)
+	}
+	if criteria.MinDiskGB > 0 {
+		params["disk_space_gte"] = strconv.Itoa(criteria.MinDiskGB)
+	}
 
 	// Add geolocation filter
 	if len(criteria.PreferredRegions) > 0 {
@@ -97,7 +118,7 @@ func (c *VastClient) SearchInstances(ctx context.Context, criteria SearchCriteri
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("search request failed: %w", err)
 	}
@@ -117,23 +138,13 @@ func (c *VastClient) SearchInstances(ctx context.Context, criteria SearchCriteri
 	return offers, nil
 }
 
-// CreateInstance launches a new instance on Vast.ai
-func (c *VastClient) CreateInstance(ctx context.Context, offer InstanceOffer, pod *corev1.Pod) (*Instance, error) {
-	// Build creation request
-	createReq := CreateInstanceRequest{
-		ClientID:      "virtual-kubelet-june",
-		Image:         "ozzuworld/june-gpu-multi:latest", // TODO: extract from pod spec
-		DiskGB:        50,
-		DockerOptions: "-p 8000:8000 -p 8001:8001 --gpus all --restart unless-stopped",
-		EnvVars: map[string]string{
-			"STT_PORT":              "8001",
-			"TTS_PORT":              "8000",
-			"CUDA_VISIBLE_DEVICES": "0",
-			"WHISPER_DEVICE":        "cuda",
-			"TTS_CACHE_PATH":        "/app/cache",
-			"COQUI_TOS_AGREED":      "1",
-		},
-		OnStart: "#!/bin/bash\necho '[VAST-K8S] Starting June GPU Multi-Service'\nnvidia-smi\n/app/start-services.sh",
+// CreateInstance launches a new instance on Vast.ai. nodeName is stamped
+// into the instance's Label (see pkg/provider/vast/api/labels.go) so the
+// state reconciler can recover pod<->instance identity after a restart.
+func (c *VastClient) CreateInstance(ctx context.Context, offer InstanceOffer, pod *corev1.Pod, nodeName string) (*Instance, error) {
+	createReq, err := c.buildCreateInstanceRequest(ctx, pod, nodeName)
+	if err != nil {
+		return nil, err
 	}
 
 	body, err := json.Marshal(createReq)
@@ -151,7 +162,7 @@ func (c *VastClient) CreateInstance(ctx context.Context, offer InstanceOffer, po
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("create instance request failed: %w", err)
 	}
@@ -253,7 +264,7 @@ func (c *VastClient) GetInstance(ctx context.Context, instanceID int) (*Instance
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("get instance request failed: %w", err)
 	}
@@ -272,6 +283,35 @@ func (c *VastClient) GetInstance(ctx context.Context, instanceID int) (*Instance
 	return &instance, nil
 }
 
+// ListInstances returns all instances currently owned by this client_id.
+func (c *VastClient) ListInstances(ctx context.Context) ([]Instance, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/instances/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("list instances request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list instances failed: %d %s", resp.StatusCode, string(body))
+	}
+
+	var listResp struct {
+		Instances []Instance `json:"instances"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("failed to decode instance list: %w", err)
+	}
+
+	return listResp.Instances, nil
+}
+
 // GetInstanceStatus returns the current status of an instance
 func (c *VastClient) GetInstanceStatus(ctx context.Context, instanceID int) (InstanceStatus, error) {
 	instance, err := c.GetInstance(ctx, instanceID)
@@ -288,6 +328,21 @@ func (c *VastClient) UpdateInstance(ctx context.Context, instanceID int, pod *co
 	return fmt.Errorf("instance updates not supported, recreate pod to get new instance")
 }
 
+// sshPool lazily builds the SSH connection pool shared by RunSSHCommand,
+// RunInContainer, and GetInstanceLogs, so instances without a configured
+// VAST_SSH_KEY still work for everything that doesn't need SSH.
+func (c *VastClient) sshPool() (*sshexec.Pool, error) {
+	c.sshPoolOnce.Do(func() {
+		config, err := sshClientConfig()
+		if err != nil {
+			c.sshPoolErr = err
+			return
+		}
+		c.sshPoolVal = sshexec.NewPool(config)
+	})
+	return c.sshPoolVal, c.sshPoolErr
+}
+
 // DestroyInstance terminates an instance
 func (c *VastClient) DestroyInstance(ctx context.Context, instanceID int) error {
 	req, err := http.NewRequestWithContext(ctx, "DELETE", 
@@ -297,7 +352,7 @@ func (c *VastClient) DestroyInstance(ctx context.Context, instanceID int) error
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return fmt.Errorf("destroy instance request failed: %w", err)
 	}
@@ -311,9 +366,3 @@ func (c *VastClient) DestroyInstance(ctx context.Context, instanceID int) error
 	klog.Infof("Instance %d destroyed", instanceID)
 	return nil
 }
-
-// GetInstanceLogs retrieves logs from an instance
-func (c *VastClient) GetInstanceLogs(ctx context.Context, instanceID int, opts ContainerLogOpts) (io.ReadCloser, error) {
-	// Vast.ai doesn't provide direct log API, return empty
-	return io.NopCloser(strings.NewReader("Vast.ai logs not available via API\n")), nil
-}
\ No newline at end of file
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// eventPollInterval is how often WatchInstanceEvents polls
+	// /instances/{id}/events for new entries. Vast.ai's API doesn't support a
+	// blocking long-poll wait, so this is a short-interval poll rather than a
+	// true push subscription - still far cheaper than recomputing every
+	// tracked pod's status on a 30 second timer.
+	eventPollInterval = 3 * time.Second
+
+	// eventStreamBufferSize bounds how many status transitions can queue up
+	// before a slow consumer starts blocking the watch goroutine.
+	eventStreamBufferSize = 8
+)
+
+// errInstanceGone is returned by fetchInstanceEvents once the instance no
+// longer exists, telling pollInstanceEvents to stop rather than retry forever.
+var errInstanceGone = errors.New("instance no longer exists")
+
+// instanceEvent is one entry from Vast.ai's per-instance event stream.
+type instanceEvent struct {
+	Status    InstanceStatus `json:"status"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// WatchInstanceEvents subscribes to instanceID's event stream, returning a
+// channel that receives a value every time Vast.ai reports a status
+// transition (e.g. starting -> running, running -> failed). The channel is
+// closed when ctx is cancelled or the instance is destroyed; callers should
+// treat closure as "subscription ended", not as an error, and fall back to
+// polling GetInstanceStatus directly.
+func (c *VastClient) WatchInstanceEvents(ctx context.Context, instanceID int) (<-chan InstanceStatus, error) {
+	events := make(chan InstanceStatus, eventStreamBufferSize)
+	go c.pollInstanceEvents(ctx, instanceID, events)
+	return events, nil
+}
+
+func (c *VastClient) pollInstanceEvents(ctx context.Context, instanceID int, events chan<- InstanceStatus) {
+	defer close(events)
+
+	log := klog.FromContext(ctx).WithValues("instanceId", instanceID)
+	var since time.Time
+	var last InstanceStatus
+
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		batch, err := c.fetchInstanceEvents(ctx, instanceID, since)
+		if errors.Is(err, errInstanceGone) {
+			log.Info("instance no longer exists, ending event subscription")
+			return
+		}
+		if err != nil {
+			log.Error(err, "instance event poll failed, will retry")
+			continue
+		}
+
+		for _, ev := range batch {
+			since = ev.Timestamp
+			if ev.Status == last {
+				continue
+			}
+			last = ev.Status
+			select {
+			case events <- ev.Status:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (c *VastClient) fetchInstanceEvents(ctx context.Context, instanceID int, since time.Time) ([]instanceEvent, error) {
+	url := fmt.Sprintf("%s/instances/%d/events", c.baseURL, instanceID)
+	if !since.IsZero() {
+		url += "?since=" + since.Format(time.RFC3339)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("instance events request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errInstanceGone
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("instance events failed: %d %s", resp.StatusCode, string(body))
+	}
+
+	var eventsResp struct {
+		Events []instanceEvent `json:"events"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&eventsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode instance events: %w", err)
+	}
+	return eventsResp.Events, nil
+}
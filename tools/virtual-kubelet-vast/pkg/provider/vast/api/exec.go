@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vkapi "github.com/virtual-kubelet/virtual-kubelet/node/api"
+)
+
+// RunInContainer execs cmd inside instanceID's managed container over its
+// pooled SSH connection (see pkg/provider/vast/api/sshexec), wiring attach's
+// Stdin/Stdout/Stderr and PTY/resize through to the remote `docker exec`.
+// Vast.ai instances run a single container, so containerName is resolved to
+// managedContainerName rather than taken from the caller.
+func (c *VastClient) RunInContainer(ctx context.Context, instanceID int, cmd string, attach vkapi.AttachIO) error {
+	instance, err := c.GetInstance(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to look up instance %d to exec command: %w", instanceID, err)
+	}
+	if instance.SSHHost == "" || instance.SSHPort == 0 {
+		return fmt.Errorf("instance %d has no SSH endpoint yet", instanceID)
+	}
+
+	pool, err := c.sshPool()
+	if err != nil {
+		return err
+	}
+
+	execFlags := "-i"
+	if attach.TTY() {
+		execFlags = "-it"
+	}
+
+	addr := fmt.Sprintf("%s:%d", instance.SSHHost, instance.SSHPort)
+	execCmd := fmt.Sprintf("docker exec %s %s %s", execFlags, managedContainerName, cmd)
+
+	return pool.Attach(ctx, instanceID, addr, execCmd, attach)
+}
+
+// RunExecProbe runs cmd inside instanceID's managed container over SSH and
+// reports whether it exited zero, the shape a corev1.ExecAction readiness
+// probe needs. It's the one-shot, non-interactive counterpart to
+// RunInContainer.
+func (c *VastClient) RunExecProbe(ctx context.Context, instanceID int, cmd []string) error {
+	execCmd := fmt.Sprintf("docker exec %s %s", managedContainerName, strings.Join(cmd, " "))
+	_, err := c.RunSSHCommand(ctx, instanceID, execCmd)
+	return err
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstanceLabel is the structured identity vast-k8s stamps into an
+// instance's single free-form Label field when launching it, since the
+// Vast.ai API has no concept of multiple labels the way Kubernetes objects
+// do. It lets a reconciler recover which pod and node an instance belongs to
+// from the instance alone, without relying on any in-memory state
+// surviving a process restart.
+type InstanceLabel struct {
+	UID  string // pod.UID; the orphan-GC reconciler's original identity key
+	Node string // the virtual node the pod was scheduled to
+	Pod  string // "<namespace>/<name>", for the state reconciler and logging
+}
+
+// FormatInstanceLabel renders l into the string stored in
+// CreateInstanceRequest.Label.
+func FormatInstanceLabel(l InstanceLabel) string {
+	return fmt.Sprintf("uid=%s,node=%s,pod=%s", l.UID, l.Node, l.Pod)
+}
+
+// ParseInstanceLabel parses a label written by FormatInstanceLabel. It
+// reports false if label doesn't carry a UID, which includes instances
+// launched before this format existed - callers should treat those as
+// unidentifiable rather than guessing.
+func ParseInstanceLabel(label string) (InstanceLabel, bool) {
+	var l InstanceLabel
+	for _, part := range strings.Split(label, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "uid":
+			l.UID = kv[1]
+		case "node":
+			l.Node = kv[1]
+		case "pod":
+			l.Pod = kv[1]
+		}
+	}
+	return l, l.UID != ""
+}
@@ -24,6 +24,12 @@ type SearchCriteria struct {
 	PreferredRegions  []string
 	VerifiedOnly      bool
 	RentableOnly      bool
+
+	// MinDLPerf and MinDiskGB are 0-disables-the-filter, like the other
+	// minimums above; they exist mainly so a VastOfferTemplate CR can impose
+	// a DLPerf floor or disk size without widening every other caller.
+	MinDLPerf float64
+	MinDiskGB int
 }
 
 // InstanceOffer represents an available instance from Vast.ai search
@@ -47,6 +53,7 @@ type InstanceOffer struct {
 	DiskSpace        int     `json:"disk_space"`
 	StartupScript    string  `json:"startup_script"`
 	HostRunTime      int     `json:"host_run_time"`
+	DLPerf           float64 `json:"dlperf"`
 }
 
 // Instance represents a running Vast.ai instance
@@ -63,6 +70,7 @@ type Instance struct {
 	DPH             float64            `json:"dph_total"`
 	Geolocation     string             `json:"geolocation"`
 	Label           string             `json:"label"`
+	ClientID        string             `json:"client_id"`
 }
 
 // CreateInstanceRequest represents the request to create a new instance
@@ -73,7 +81,9 @@ type CreateInstanceRequest struct {
 	DockerOptions string            `json:"args"`
 	EnvVars       map[string]string `json:"env"`
 	OnStart       string            `json:"onstart"`
-	RunType       string            `json:"runtype"` // "ssh" or "jupyter"
+	RunType       string            `json:"runtype"`     // "ssh" or "jupyter"
+	Label         string            `json:"label"`       // FormatInstanceLabel output (pod UID, node, pod) for GC/reconciliation
+	ImageLogin    string            `json:"image_login"` // "-u <user> -p <password>" for private registries, empty if none
 }
 
 // CreateInstanceResponse represents the response from instance creation
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// buildCreateInstanceRequest translates a Pod spec into a Vast.ai CreateInstanceRequest.
+// It rejects multi-container pods until sidecar support lands. nodeName is
+// stamped into the instance's Label alongside the pod's UID so the state
+// reconciler can recover which virtual node owns the instance after a
+// restart; see pkg/provider/vast/api/labels.go.
+func (c *VastClient) buildCreateInstanceRequest(ctx context.Context, pod *corev1.Pod, nodeName string) (CreateInstanceRequest, error) {
+	if len(pod.Spec.Containers) == 0 {
+		return CreateInstanceRequest{}, fmt.Errorf("pod %s/%s has no containers", pod.Namespace, pod.Name)
+	}
+	if len(pod.Spec.Containers) > 1 {
+		return CreateInstanceRequest{}, fmt.Errorf("pod %s/%s has %d containers, multi-container pods are not yet supported", pod.Namespace, pod.Name, len(pod.Spec.Containers))
+	}
+
+	container := pod.Spec.Containers[0]
+
+	envVars, err := c.resolveEnv(ctx, pod.Namespace, container)
+	if err != nil {
+		return CreateInstanceRequest{}, fmt.Errorf("failed to resolve env for pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+
+	dockerOptions := buildDockerOptions(container)
+
+	diskGB := resourceDiskGB(container)
+
+	onStart := defaultOnStart
+	if override, ok := pod.Annotations["vast.ai/onstart"]; ok && override != "" {
+		onStart = override
+	}
+
+	imageLogin, err := c.resolveImageLogin(ctx, pod.Namespace, container.Image, pod.Spec.ImagePullSecrets)
+	if err != nil {
+		return CreateInstanceRequest{}, fmt.Errorf("failed to resolve image pull secrets for pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+
+	label := FormatInstanceLabel(InstanceLabel{
+		UID:  string(pod.UID),
+		Node: nodeName,
+		Pod:  pod.Namespace + "/" + pod.Name,
+	})
+
+	return CreateInstanceRequest{
+		ClientID:      "virtual-kubelet-june",
+		Image:         container.Image,
+		DiskGB:        diskGB,
+		DockerOptions: dockerOptions,
+		EnvVars:       envVars,
+		OnStart:       onStart,
+		RunType:       "ssh",
+		Label:         label,
+		ImageLogin:    imageLogin,
+	}, nil
+}
+
+const defaultOnStart = "#!/bin/bash\necho '[VAST-K8S] Starting container'\nnvidia-smi\n"
+
+// resolveEnv merges container.Env and container.EnvFrom into a flat map, resolving
+// ConfigMap and Secret references via the kube clientset wired in main.go.
+func (c *VastClient) resolveEnv(ctx context.Context, namespace string, container corev1.Container) (map[string]string, error) {
+	env := make(map[string]string)
+
+	for _, envFrom := range container.EnvFrom {
+		switch {
+		case envFrom.ConfigMapRef != nil:
+			cm, err := c.getConfigMap(ctx, namespace, envFrom.ConfigMapRef.Name)
+			if err != nil {
+				if envFrom.ConfigMapRef.Optional != nil && *envFrom.ConfigMapRef.Optional {
+					continue
+				}
+				return nil, err
+			}
+			for k, v := range cm.Data {
+				env[envFrom.Prefix+k] = v
+			}
+		case envFrom.SecretRef != nil:
+			secret, err := c.getSecret(ctx, namespace, envFrom.SecretRef.Name)
+			if err != nil {
+				if envFrom.SecretRef.Optional != nil && *envFrom.SecretRef.Optional {
+					continue
+				}
+				return nil, err
+			}
+			for k, v := range secret.Data {
+				env[envFrom.Prefix+k] = string(v)
+			}
+		}
+	}
+
+	for _, e := range container.Env {
+		if e.Value != "" || e.ValueFrom == nil {
+			env[e.Name] = e.Value
+			continue
+		}
+
+		switch {
+		case e.ValueFrom.ConfigMapKeyRef != nil:
+			ref := e.ValueFrom.ConfigMapKeyRef
+			cm, err := c.getConfigMap(ctx, namespace, ref.Name)
+			if err != nil {
+				if ref.Optional != nil && *ref.Optional {
+					continue
+				}
+				return nil, err
+			}
+			env[e.Name] = cm.Data[ref.Key]
+		case e.ValueFrom.SecretKeyRef != nil:
+			ref := e.ValueFrom.SecretKeyRef
+			secret, err := c.getSecret(ctx, namespace, ref.Name)
+			if err != nil {
+				if ref.Optional != nil && *ref.Optional {
+					continue
+				}
+				return nil, err
+			}
+			env[e.Name] = string(secret.Data[ref.Key])
+		}
+	}
+
+	return env, nil
+}
+
+func (c *VastClient) getConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	if c.kubeClient == nil {
+		return nil, fmt.Errorf("no kube clientset configured, cannot resolve ConfigMap %s/%s", namespace, name)
+	}
+	return c.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *VastClient) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	if c.kubeClient == nil {
+		return nil, fmt.Errorf("no kube clientset configured, cannot resolve Secret %s/%s", namespace, name)
+	}
+	return c.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// dockerConfigJSON mirrors the subset of ~/.docker/config.json that Kubernetes stores
+// in kubernetes.io/dockerconfigjson secrets.
+type dockerConfigJSON struct {
+	Auths map[string]dockerAuthEntry `json:"auths"`
+}
+
+type dockerAuthEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"` // base64("username:password"), used when Username/Password are empty
+}
+
+// resolveImageLogin finds the imagePullSecrets entry matching image's registry and
+// returns it as the "-u <user> -p <password>" string Vast.ai expects for its
+// image_login field, or "" if image uses no private registry credentials.
+func (c *VastClient) resolveImageLogin(ctx context.Context, namespace, image string, imagePullSecrets []corev1.LocalObjectReference) (string, error) {
+	if len(imagePullSecrets) == 0 {
+		return "", nil
+	}
+
+	registry := imageRegistry(image)
+
+	for _, ref := range imagePullSecrets {
+		secret, err := c.getSecret(ctx, namespace, ref.Name)
+		if err != nil {
+			return "", err
+		}
+
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			continue
+		}
+
+		var cfg dockerConfigJSON
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return "", fmt.Errorf("failed to parse %s in secret %s/%s: %w", corev1.DockerConfigJsonKey, namespace, ref.Name, err)
+		}
+
+		entry, ok := cfg.Auths[registry]
+		if !ok {
+			continue
+		}
+
+		username, password := entry.Username, entry.Password
+		if username == "" && password == "" && entry.Auth != "" {
+			username, password, err = decodeDockerAuth(entry.Auth)
+			if err != nil {
+				return "", fmt.Errorf("failed to decode auth for registry %s in secret %s/%s: %w", registry, namespace, ref.Name, err)
+			}
+		}
+
+		return fmt.Sprintf("-u %s -p %s", username, password), nil
+	}
+
+	return "", nil
+}
+
+// imageRegistry extracts the registry host from an image reference, defaulting to
+// Docker Hub's index when the image has no explicit registry (matching how
+// imagePullSecrets are keyed for official images).
+func imageRegistry(image string) string {
+	const dockerHubRegistry = "https://index.docker.io/v1/"
+
+	ref := strings.SplitN(image, "/", 2)
+	if len(ref) == 1 {
+		return dockerHubRegistry
+	}
+
+	host := ref[0]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return dockerHubRegistry
+	}
+
+	return host
+}
+
+func decodeDockerAuth(auth string) (username, password string, err error) {
+	decoded, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return "", "", err
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("malformed auth string")
+	}
+	return parts[0], parts[1], nil
+}
+
+// buildDockerOptions maps containerPorts and GPU requests to vast.ai `args` docker options.
+func buildDockerOptions(container corev1.Container) string {
+	var opts []string
+
+	for _, port := range container.Ports {
+		p := port.ContainerPort
+		opts = append(opts, fmt.Sprintf("-p %d:%d", p, p))
+	}
+
+	if gpuQty, ok := container.Resources.Requests["nvidia.com/gpu"]; ok && !gpuQty.IsZero() {
+		opts = append(opts, "--gpus all")
+	} else if gpuQty, ok := container.Resources.Limits["nvidia.com/gpu"]; ok && !gpuQty.IsZero() {
+		opts = append(opts, "--gpus all")
+	}
+
+	opts = append(opts, "--restart unless-stopped")
+
+	return strings.Join(opts, " ")
+}
+
+// resourceDiskGB derives the requested disk size from ephemeral-storage requests/limits,
+// falling back to a conservative default.
+func resourceDiskGB(container corev1.Container) int {
+	const defaultDiskGB = 50
+
+	if qty, ok := container.Resources.Requests[corev1.ResourceEphemeralStorage]; ok {
+		return quantityToGB(qty, defaultDiskGB)
+	}
+	if qty, ok := container.Resources.Limits[corev1.ResourceEphemeralStorage]; ok {
+		return quantityToGB(qty, defaultDiskGB)
+	}
+	return defaultDiskGB
+}
+
+func quantityToGB(qty resource.Quantity, fallback int) int {
+	bytes := qty.Value()
+	if bytes <= 0 {
+		return fallback
+	}
+	gb := bytes / (1024 * 1024 * 1024)
+	if gb <= 0 {
+		return 1
+	}
+	return int(gb)
+}
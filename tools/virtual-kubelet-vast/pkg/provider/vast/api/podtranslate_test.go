@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildCreateInstanceRequest_EnvAndPorts(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "june-config", Namespace: "default"},
+			Data:       map[string]string{"WHISPER_DEVICE": "cuda"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "june-secret", Namespace: "default"},
+			Data:       map[string][]byte{"API_TOKEN": []byte("s3cr3t")},
+		},
+	)
+	c := &VastClient{kubeClient: kubeClient}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "june-gpu",
+			Annotations: map[string]string{"vast.ai/onstart": "#!/bin/bash\necho custom"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Image: "ozzuworld/june-gpu-multi:v2",
+					Ports: []corev1.ContainerPort{
+						{ContainerPort: 8000},
+						{ContainerPort: 8001},
+					},
+					Env: []corev1.EnvVar{
+						{Name: "STT_PORT", Value: "8001"},
+						{
+							Name: "API_TOKEN",
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "june-secret"},
+									Key:                  "API_TOKEN",
+								},
+							},
+						},
+					},
+					EnvFrom: []corev1.EnvFromSource{
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "june-config"}}},
+					},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							"nvidia.com/gpu": resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	req, err := c.buildCreateInstanceRequest(context.Background(), pod, "test-node")
+	if err != nil {
+		t.Fatalf("buildCreateInstanceRequest() error = %v", err)
+	}
+
+	if req.Image != "ozzuworld/june-gpu-multi:v2" {
+		t.Errorf("Image = %q, want %q", req.Image, "ozzuworld/june-gpu-multi:v2")
+	}
+	if req.EnvVars["STT_PORT"] != "8001" {
+		t.Errorf("STT_PORT env = %q, want 8001", req.EnvVars["STT_PORT"])
+	}
+	if req.EnvVars["WHISPER_DEVICE"] != "cuda" {
+		t.Errorf("WHISPER_DEVICE env (from ConfigMap) = %q, want cuda", req.EnvVars["WHISPER_DEVICE"])
+	}
+	if req.EnvVars["API_TOKEN"] != "s3cr3t" {
+		t.Errorf("API_TOKEN env (from Secret) = %q, want s3cr3t", req.EnvVars["API_TOKEN"])
+	}
+	if req.OnStart != "#!/bin/bash\necho custom" {
+		t.Errorf("OnStart = %q, want annotation override", req.OnStart)
+	}
+	if !strings.Contains(req.DockerOptions, "-p 8000:8000") || !strings.Contains(req.DockerOptions, "-p 8001:8001") {
+		t.Errorf("DockerOptions = %q, want both ports mapped", req.DockerOptions)
+	}
+	if !strings.Contains(req.DockerOptions, "--gpus all") {
+		t.Errorf("DockerOptions = %q, want --gpus all for nvidia.com/gpu request", req.DockerOptions)
+	}
+}
+
+func TestBuildCreateInstanceRequest_ImageLogin(t *testing.T) {
+	dockerConfig := `{"auths":{"https://index.docker.io/v1/":{"auth":"` +
+		base64.StdEncoding.EncodeToString([]byte("myuser:mypass")) + `"}}}`
+
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "regcred", Namespace: "default"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(dockerConfig)},
+		},
+	)
+	c := &VastClient{kubeClient: kubeClient}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "june-gpu"},
+		Spec: corev1.PodSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}},
+			Containers: []corev1.Container{
+				{Image: "ozzuworld/june-gpu-multi:v2"},
+			},
+		},
+	}
+
+	req, err := c.buildCreateInstanceRequest(context.Background(), pod, "test-node")
+	if err != nil {
+		t.Fatalf("buildCreateInstanceRequest() error = %v", err)
+	}
+
+	if req.ImageLogin != "-u myuser -p mypass" {
+		t.Errorf("ImageLogin = %q, want %q", req.ImageLogin, "-u myuser -p mypass")
+	}
+}
+
+func TestBuildCreateInstanceRequest_NoImagePullSecrets(t *testing.T) {
+	c := &VastClient{}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "june-gpu"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Image: "ozzuworld/june-gpu-multi:v2"}},
+		},
+	}
+
+	req, err := c.buildCreateInstanceRequest(context.Background(), pod, "test-node")
+	if err != nil {
+		t.Fatalf("buildCreateInstanceRequest() error = %v", err)
+	}
+	if req.ImageLogin != "" {
+		t.Errorf("ImageLogin = %q, want empty string when no imagePullSecrets", req.ImageLogin)
+	}
+}
+
+func TestBuildCreateInstanceRequest_RejectsMultiContainer(t *testing.T) {
+	c := &VastClient{}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "multi"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Image: "a"},
+				{Image: "b"},
+			},
+		},
+	}
+
+	if _, err := c.buildCreateInstanceRequest(context.Background(), pod, "test-node"); err == nil {
+		t.Fatal("expected error for multi-container pod, got nil")
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultQPS and DefaultBurst keep us well under Vast.ai's published rate limits
+	// even when several pods are churning instances concurrently.
+	DefaultQPS   = 2.0
+	DefaultBurst = 10
+
+	maxRetries     = 5
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vast_client_requests_total",
+		Help: "Total number of HTTP requests issued to the Vast.ai API, labeled by method and outcome.",
+	}, []string{"method", "outcome"})
+
+	retriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vast_client_retries_total",
+		Help: "Total number of retried HTTP requests to the Vast.ai API, labeled by reason.",
+	}, []string{"reason"})
+
+	rateLimitWaitSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "vast_client_rate_limit_wait_seconds",
+		Help:    "Time spent waiting on the client-side token bucket before a Vast.ai API call.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal, retriesTotal, rateLimitWaitSeconds)
+}
+
+// do issues req through the client's token-bucket limiter, retrying on 429/5xx
+// responses with exponential backoff that honors the Retry-After header. Callers
+// own closing the returned response body.
+func (c *VastClient) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	waitStart := time.Now()
+	if err := c.limiter().Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+	rateLimitWaitSeconds.Observe(time.Since(waitStart).Seconds())
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body for retry: %w", err)
+		}
+		req.Body.Close()
+	}
+
+	backoff := initialBackoff
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		if attempt > 0 {
+			if waitErr := c.limiter().Wait(ctx); waitErr != nil {
+				return nil, fmt.Errorf("rate limiter wait failed: %w", waitErr)
+			}
+		}
+
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			requestsTotal.WithLabelValues(req.Method, "error").Inc()
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			requestsTotal.WithLabelValues(req.Method, strconv.Itoa(resp.StatusCode)).Inc()
+			return resp, nil
+		}
+
+		if attempt == maxRetries {
+			requestsTotal.WithLabelValues(req.Method, strconv.Itoa(resp.StatusCode)).Inc()
+			return resp, nil
+		}
+
+		reason := "server-error"
+		wait := backoff
+		if resp.StatusCode == http.StatusTooManyRequests {
+			reason = "rate-limited"
+			if ra := retryAfterDuration(resp.Header.Get("Retry-After")); ra > 0 {
+				wait = ra
+			}
+		}
+		retriesTotal.WithLabelValues(reason).Inc()
+		klog.Infof("Vast.ai API %s %s returned %d, retrying in %s (attempt %d/%d)",
+			req.Method, req.URL.Path, resp.StatusCode, wait, attempt+1, maxRetries)
+
+		resp.Body.Close()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		backoff = time.Duration(math.Min(float64(backoff*2), float64(maxBackoff)))
+	}
+
+	return resp, err
+}
+
+// retryAfterDuration parses the Retry-After header, which may be either a number of
+// seconds or an HTTP-date. Returns 0 if the header is absent or unparseable.
+func retryAfterDuration(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func (c *VastClient) limiter() *rate.Limiter {
+	if c.rateLimiter == nil {
+		c.rateLimiter = rate.NewLimiter(rate.Limit(DefaultQPS), DefaultBurst)
+	}
+	return c.rateLimiter
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// RunSSHCommand runs cmd on instanceID's pooled SSH connection and returns
+// its combined stdout/stderr output. It's the one-shot counterpart to
+// GetInstanceLogs: that streams a long-running `docker logs -f`, this waits
+// for a single command to finish and returns its output, the shape
+// pkg/provider/vast/stats needs for nvidia-smi and cgroup reads.
+func (c *VastClient) RunSSHCommand(ctx context.Context, instanceID int, cmd string) (string, error) {
+	instance, err := c.GetInstance(ctx, instanceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up instance %d to run command: %w", instanceID, err)
+	}
+	if instance.SSHHost == "" || instance.SSHPort == 0 {
+		return "", fmt.Errorf("instance %d has no SSH endpoint yet", instanceID)
+	}
+
+	pool, err := c.sshPool()
+	if err != nil {
+		return "", err
+	}
+
+	addr := fmt.Sprintf("%s:%d", instance.SSHHost, instance.SSHPort)
+	client, err := pool.Get(ctx, instanceID, addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to dial SSH for instance %d: %w", instanceID, err)
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		pool.Evict(instanceID)
+		return "", fmt.Errorf("failed to open SSH session for instance %d: %w", instanceID, err)
+	}
+	defer session.Close()
+
+	out, err := session.CombinedOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("command %q failed on instance %d: %w", cmd, instanceID, err)
+	}
+	return string(out), nil
+}
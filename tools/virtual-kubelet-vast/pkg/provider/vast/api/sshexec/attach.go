@@ -0,0 +1,59 @@
+package sshexec
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+	vkapi "github.com/virtual-kubelet/virtual-kubelet/node/api"
+)
+
+// Attach runs cmd over instanceID's pooled SSH connection, wiring attach's
+// Stdin/Stdout/Stderr into the session. A PTY is allocated and
+// attach.Resize() forwarded as SSH window-change requests when attach.TTY()
+// is set. It blocks until cmd exits.
+func (p *Pool) Attach(ctx context.Context, instanceID int, addr string, cmd string, attach vkapi.AttachIO) error {
+	client, err := p.Get(ctx, instanceID, addr)
+	if err != nil {
+		return err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		p.Evict(instanceID)
+		return fmt.Errorf("failed to open SSH session for instance %d: %w", instanceID, err)
+	}
+	defer session.Close()
+
+	if stdin := attach.Stdin(); stdin != nil {
+		session.Stdin = stdin
+	}
+	session.Stdout = attach.Stdout()
+	session.Stderr = attach.Stderr()
+
+	if attach.TTY() {
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
+		if err := session.RequestPty("xterm", 80, 24, modes); err != nil {
+			return fmt.Errorf("failed to allocate PTY for instance %d: %w", instanceID, err)
+		}
+		go forwardResize(session, attach.Resize())
+	}
+
+	if err := session.Run(cmd); err != nil {
+		return fmt.Errorf("command %q failed on instance %d: %w", cmd, instanceID, err)
+	}
+	return nil
+}
+
+// forwardResize relays terminal resize events to the SSH session until
+// resize is closed, which the virtual-kubelet API server does once the
+// client detaches.
+func forwardResize(session *ssh.Session, resize <-chan vkapi.TermSize) {
+	for size := range resize {
+		session.WindowChange(int(size.Height), int(size.Width))
+	}
+}
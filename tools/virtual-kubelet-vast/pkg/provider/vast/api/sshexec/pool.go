@@ -0,0 +1,159 @@
+// Package sshexec holds the SSH connection pool and exec/attach primitives
+// that back RunInContainer and log streaming for Vast.ai instances. It's
+// split out of pkg/provider/vast/api so the pooling and keepalive logic can
+// be exercised independently of the HTTP client that talks to the Vast.ai
+// control plane.
+package sshexec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+)
+
+const (
+	dialRetryFor   = 1 * time.Minute // mirrors the grace period after waitForInstanceReady
+	keepaliveEvery = 30 * time.Second
+)
+
+// Pool caches one authenticated SSH connection per Vast.ai instance so
+// repeated `kubectl exec`/`kubectl logs -f` calls against the same instance
+// reuse a live connection instead of re-dialing and re-authenticating every
+// time.
+type Pool struct {
+	config *ssh.ClientConfig
+
+	mu    sync.Mutex
+	conns map[int]*pooledConn
+}
+
+type pooledConn struct {
+	client *ssh.Client
+	cancel context.CancelFunc
+}
+
+// NewPool creates a connection pool that authenticates new connections with config.
+func NewPool(config *ssh.ClientConfig) *Pool {
+	return &Pool{config: config, conns: make(map[int]*pooledConn)}
+}
+
+// Get returns instanceID's cached connection to addr, dialing (and retrying
+// for dialRetryFor, since instances can take a few seconds to accept
+// connections right after they report ready) and caching a new one if none
+// exists or the cached one no longer answers.
+func (p *Pool) Get(ctx context.Context, instanceID int, addr string) (*ssh.Client, error) {
+	if client := p.cached(instanceID); client != nil {
+		return client, nil
+	}
+
+	client, err := p.dialWithRetry(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s for instance %d: %w", addr, instanceID, err)
+	}
+
+	connCtx, cancel := context.WithCancel(context.Background())
+	go p.keepalive(connCtx, instanceID, client)
+
+	p.mu.Lock()
+	p.conns[instanceID] = &pooledConn{client: client, cancel: cancel}
+	p.mu.Unlock()
+
+	return client, nil
+}
+
+// cached returns instanceID's pooled client if one exists and still answers
+// a keepalive, evicting it first if it doesn't.
+func (p *Pool) cached(instanceID int) *ssh.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.conns[instanceID]
+	if !ok {
+		return nil
+	}
+	if _, _, err := c.client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+		delete(p.conns, instanceID)
+		c.cancel()
+		c.client.Close()
+		return nil
+	}
+	return c.client
+}
+
+func (p *Pool) dialWithRetry(ctx context.Context, addr string) (*ssh.Client, error) {
+	deadline := time.Now().Add(dialRetryFor)
+
+	var lastErr error
+	for {
+		client, err := ssh.Dial("tcp", addr, p.config)
+		if err == nil {
+			return client, nil
+		}
+		lastErr = err
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("giving up after %s: %w", dialRetryFor, lastErr)
+		}
+
+		klog.Infof("SSH dial to %s failed, retrying: %v", addr, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// keepalive pings client periodically so a connection the instance side
+// dropped silently (NAT timeout, sshd restart) is noticed and evicted
+// instead of being handed to the next caller as if it still worked.
+func (p *Pool) keepalive(ctx context.Context, instanceID int, client *ssh.Client) {
+	ticker := time.NewTicker(keepaliveEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				klog.Infof("SSH keepalive to instance %d failed, evicting from pool: %v", instanceID, err)
+				p.Evict(instanceID)
+				return
+			}
+		}
+	}
+}
+
+// Evict closes and removes instanceID's cached connection, if any. Safe to
+// call when nothing is cached.
+func (p *Pool) Evict(instanceID int) {
+	p.mu.Lock()
+	c, ok := p.conns[instanceID]
+	if ok {
+		delete(p.conns, instanceID)
+	}
+	p.mu.Unlock()
+
+	if ok {
+		c.cancel()
+		c.client.Close()
+	}
+}
+
+// Close tears down every pooled connection.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = make(map[int]*pooledConn)
+	p.mu.Unlock()
+
+	for _, c := range conns {
+		c.cancel()
+		c.client.Close()
+	}
+}
@@ -0,0 +1,54 @@
+package sshexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// StreamCommand starts cmd over instanceID's pooled SSH connection and
+// returns its stdout as an io.ReadCloser. Closing it tears down the SSH
+// session only, so the pooled connection underneath stays open for the next
+// caller.
+func (p *Pool) StreamCommand(ctx context.Context, instanceID int, addr string, cmd string) (io.ReadCloser, error) {
+	client, err := p.Get(ctx, instanceID, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		p.Evict(instanceID)
+		return nil, fmt.Errorf("failed to open SSH session for instance %d: %w", instanceID, err)
+	}
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to attach stdout for instance %d: %w", instanceID, err)
+	}
+
+	if err := session.Start(cmd); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to start %q on instance %d: %w", cmd, instanceID, err)
+	}
+
+	return &commandStream{stdout: stdout, session: session}, nil
+}
+
+// commandStream is an io.ReadCloser backed by a live SSH session running a
+// single command.
+type commandStream struct {
+	stdout  io.Reader
+	session *ssh.Session
+}
+
+func (s *commandStream) Read(p []byte) (int, error) {
+	return s.stdout.Read(p)
+}
+
+func (s *commandStream) Close() error {
+	return s.session.Close()
+}
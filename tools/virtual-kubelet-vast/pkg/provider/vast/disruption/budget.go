@@ -0,0 +1,58 @@
+package disruption
+
+import "sync"
+
+// Budget caps how many instances may be disrupted at once in a given
+// namespace, expressed as a percentage of that namespace's instance count -
+// the same "don't take out more than N% of the fleet at a time" guardrail
+// Karpenter applies to node consolidation.
+type Budget struct {
+	maxPercent float64
+
+	mu     sync.Mutex
+	active map[string]int // namespace -> instances currently mid-disruption
+}
+
+// NewBudget creates a Budget allowing at most maxPercent of a namespace's
+// instances to be disrupted concurrently. maxPercent <= 0 disables the cap.
+func NewBudget(maxPercent float64) *Budget {
+	return &Budget{
+		maxPercent: maxPercent,
+		active:     make(map[string]int),
+	}
+}
+
+// TryAcquire reserves one disruption slot for namespace if doing so would not
+// exceed the configured budget given namespaceInstanceCount instances
+// currently running there. It returns false, leaving the budget unchanged, if
+// the slot would push the namespace over budget.
+func (b *Budget) TryAcquire(namespace string, namespaceInstanceCount int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.maxPercent <= 0 || namespaceInstanceCount <= 0 {
+		b.active[namespace]++
+		return true
+	}
+
+	allowed := int(float64(namespaceInstanceCount) * b.maxPercent / 100)
+	if allowed < 1 {
+		allowed = 1
+	}
+	if b.active[namespace] >= allowed {
+		return false
+	}
+
+	b.active[namespace]++
+	return true
+}
+
+// Release frees the disruption slot reserved by a prior TryAcquire.
+func (b *Budget) Release(namespace string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.active[namespace] > 0 {
+		b.active[namespace]--
+	}
+}
@@ -0,0 +1,40 @@
+package disruption
+
+import "testing"
+
+func TestBudget_TryAcquire_RespectsMaxPercent(t *testing.T) {
+	b := NewBudget(20)
+
+	if !b.TryAcquire("default", 10) {
+		t.Fatal("TryAcquire() = false for first slot of 10 instances at 20%, want true")
+	}
+	if b.TryAcquire("default", 10) {
+		t.Fatal("TryAcquire() = true for second slot of 10 instances at 20%, want false (budget exhausted)")
+	}
+
+	b.Release("default")
+	if !b.TryAcquire("default", 10) {
+		t.Fatal("TryAcquire() = false after Release(), want true")
+	}
+}
+
+func TestBudget_TryAcquire_ZeroPercentDisablesCap(t *testing.T) {
+	b := NewBudget(0)
+
+	for i := 0; i < 5; i++ {
+		if !b.TryAcquire("default", 1) {
+			t.Fatalf("TryAcquire() = false on attempt %d with maxPercent disabled, want true", i)
+		}
+	}
+}
+
+func TestBudget_TryAcquire_NamespacesAreIndependent(t *testing.T) {
+	b := NewBudget(20)
+
+	if !b.TryAcquire("ns-a", 10) {
+		t.Fatal("TryAcquire() = false for ns-a, want true")
+	}
+	if !b.TryAcquire("ns-b", 10) {
+		t.Fatal("TryAcquire() = false for ns-b, want true (separate namespace budget)")
+	}
+}
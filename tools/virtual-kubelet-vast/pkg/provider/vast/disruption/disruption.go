@@ -0,0 +1,276 @@
+// Package disruption proactively replaces running Vast.ai instances with
+// better ones, borrowing the disruption model Karpenter applies to nodes:
+// consolidate onto cheaper offers, replace instances whose launch criteria
+// have drifted from what's currently desired, and rotate instances out once
+// they pass a maximum age. Unlike the GC reconciler in the vast package -
+// which only removes instances nothing wants anymore - this controller
+// replaces instances that are still wanted, just not optimally.
+package disruption
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/metrics"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/scheduler"
+)
+
+const (
+	// DefaultReconcileInterval is how often the controller looks for
+	// instances to disrupt.
+	DefaultReconcileInterval = 2 * time.Minute
+
+	// DefaultConsolidationThreshold requires a replacement candidate to be at
+	// least 15% cheaper than the running instance before it's worth the churn
+	// of cutting over.
+	DefaultConsolidationThreshold = 0.15
+
+	// DefaultMaxInstanceAge rotates an instance out after a week, bounding
+	// exposure to a single host's reliability given Vast's variable hardware.
+	DefaultMaxInstanceAge = 7 * 24 * time.Hour
+
+	// DefaultMaxDisruptionPercent caps concurrent disruptions at 20% of a
+	// namespace's instances, the same conservative default Karpenter ships.
+	DefaultMaxDisruptionPercent = 20.0
+)
+
+// EndpointUpdater is the subset of vast.EndpointManager the controller needs
+// to cut service traffic over to a replacement instance. It's expressed as an
+// interface, rather than importing the vast package directly, to avoid an
+// import cycle (vast.VastProvider wires this controller).
+type EndpointUpdater interface {
+	UpdatePodEndpoints(ctx context.Context, pod *corev1.Pod, instance *api.Instance) error
+}
+
+// Config holds the thresholds that decide when an instance is disrupted.
+type Config struct {
+	ConsolidationThreshold float64
+	MaxInstanceAge         time.Duration
+	MaxDisruptionPercent   float64
+}
+
+// DefaultConfig returns the thresholds described on the Default* constants above.
+func DefaultConfig() Config {
+	return Config{
+		ConsolidationThreshold: DefaultConsolidationThreshold,
+		MaxInstanceAge:         DefaultMaxInstanceAge,
+		MaxDisruptionPercent:   DefaultMaxDisruptionPercent,
+	}
+}
+
+// launchRecord is what the controller remembers about an instance it didn't
+// necessarily launch itself but has been told to watch over.
+type launchRecord struct {
+	pod        *corev1.Pod
+	instance   api.Instance
+	configHash string
+	launchedAt time.Time
+}
+
+// Controller runs the consolidation/drift/expiration reconciliation loop for
+// a single virtual node's instances.
+type Controller struct {
+	client     *api.VastClient
+	scheduler  *scheduler.InstanceScheduler
+	endpoints  EndpointUpdater
+	nodeName   string
+	config     Config
+	budget     *Budget
+	recorder   record.EventRecorder
+	interval   time.Duration
+
+	mu      sync.Mutex
+	records map[string]*launchRecord // pod name -> record
+}
+
+// NewController creates a disruption controller for nodeName. kubeClient is
+// used only to emit Kubernetes Events describing why an instance was rotated.
+func NewController(client *api.VastClient, instanceScheduler *scheduler.InstanceScheduler, endpoints EndpointUpdater, kubeClient kubernetes.Interface, nodeName string, config Config) *Controller {
+	return &Controller{
+		client:    client,
+		scheduler: instanceScheduler,
+		endpoints: endpoints,
+		nodeName:  nodeName,
+		config:    config,
+		budget:    NewBudget(config.MaxDisruptionPercent),
+		recorder:  newEventRecorder(kubeClient, nodeName),
+		interval:  DefaultReconcileInterval,
+		records:   make(map[string]*launchRecord),
+	}
+}
+
+func newEventRecorder(kubeClient kubernetes.Interface, nodeName string) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "vast-disruption-controller", Host: nodeName})
+}
+
+// RecordLaunch tells the controller about an instance it should watch for
+// disruption, along with the pod spec and scheduling config it was launched
+// against. Call it after every successful CreatePod.
+func (c *Controller) RecordLaunch(pod *corev1.Pod, instance *api.Instance, config *scheduler.SchedulerConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records[pod.Name] = &launchRecord{
+		pod:        pod,
+		instance:   *instance,
+		configHash: configHash(config, pod),
+		launchedAt: time.Now(),
+	}
+}
+
+// Forget stops tracking podName, called after DeletePod.
+func (c *Controller) Forget(podName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.records, podName)
+}
+
+// Run starts the reconciliation loop and blocks until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context) {
+	log := klog.FromContext(ctx).WithValues("component", "vast-disruption")
+	log.Info("Starting disruption controller")
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.reconcileOnce(ctx, log)
+		}
+	}
+}
+
+func (c *Controller) reconcileOnce(ctx context.Context, log klog.Logger) {
+	c.mu.Lock()
+	records := make([]*launchRecord, 0, len(c.records))
+	for _, r := range c.records {
+		records = append(records, r)
+	}
+	c.mu.Unlock()
+
+	countByNamespace := make(map[string]int)
+	for _, r := range records {
+		countByNamespace[r.pod.Namespace]++
+	}
+
+	for _, r := range records {
+		reason, detail := c.disruptionReason(r)
+		if reason == "" {
+			continue
+		}
+
+		if !c.budget.TryAcquire(r.pod.Namespace, countByNamespace[r.pod.Namespace]) {
+			log.Info("skipping disruption, namespace budget exhausted", "pod", r.pod.Name, "reason", reason)
+			continue
+		}
+
+		go func(r *launchRecord, reason, detail string) {
+			defer c.budget.Release(r.pod.Namespace)
+			c.replace(ctx, log, r, reason, detail)
+		}(r, reason, detail)
+	}
+}
+
+// disruptionReason checks expiration, drift, and consolidation in that order
+// - cheapest checks first - and returns the first reason that applies. An
+// instance that is neither expired nor drifted is always returned as a
+// Consolidation candidate, so it never returns "".
+func (c *Controller) disruptionReason(r *launchRecord) (reason, detail string) {
+	if age := time.Since(r.launchedAt); age > c.config.MaxInstanceAge {
+		return "Expired", fmt.Sprintf("instance age %s exceeds MaxInstanceAge %s", age.Round(time.Second), c.config.MaxInstanceAge)
+	}
+
+	if desired := configHash(c.currentConfig(), r.pod); desired != r.configHash {
+		return "Drifted", "scheduling criteria changed since launch"
+	}
+
+	// Every instance that isn't drifted or expired is a consolidation
+	// candidate; replace() only commits to the swap if a cheaper offer
+	// actually clears ConsolidationThreshold.
+	return "Consolidation", "checking for a cheaper offer"
+}
+
+// currentConfig exposes the scheduler's live SchedulerConfig so drift
+// detection compares against what's desired *now*, not at launch time.
+func (c *Controller) currentConfig() *scheduler.SchedulerConfig {
+	return c.scheduler.Config()
+}
+
+// replace finds a candidate instance, and for Consolidation only commits to
+// replacing the running instance if the candidate clears
+// ConsolidationThreshold; Drifted and Expired instances are always replaced
+// once flagged, since waiting for a better offer defeats the point.
+func (c *Controller) replace(ctx context.Context, log klog.Logger, r *launchRecord, reason, detail string) {
+	log = log.WithValues("pod", r.pod.Name, "instanceId", r.instance.ID, "reason", reason)
+	log.Info("evaluating instance for disruption", "detail", detail)
+
+	candidate, err := c.scheduler.FindBestOffer(ctx, r.pod)
+	if err != nil {
+		log.Error(err, "failed to find replacement candidate")
+		return
+	}
+
+	if reason == "Consolidation" {
+		savings := (r.instance.DPH - candidate.Offer.DPH) / r.instance.DPH
+		if r.instance.DPH <= 0 || savings < c.config.ConsolidationThreshold {
+			return
+		}
+		detail = fmt.Sprintf("candidate instance %d is %.1f%% cheaper ($%.3f/hr vs $%.3f/hr)", candidate.Offer.ID, savings*100, candidate.Offer.DPH, r.instance.DPH)
+	}
+
+	replacement, err := c.scheduler.LaunchOffer(ctx, candidate, r.pod)
+	if err != nil {
+		log.Error(err, "failed to launch replacement instance")
+		return
+	}
+
+	if err := c.endpoints.UpdatePodEndpoints(ctx, r.pod, replacement); err != nil {
+		log.Error(err, "failed to cut endpoints over to replacement instance, destroying replacement", "replacementInstanceId", replacement.ID)
+		if destroyErr := c.client.DestroyInstance(ctx, replacement.ID); destroyErr != nil {
+			log.Error(destroyErr, "failed to clean up replacement instance after failed cutover", "replacementInstanceId", replacement.ID)
+		}
+		return
+	}
+
+	if err := c.client.DestroyInstance(ctx, r.instance.ID); err != nil {
+		log.Error(err, "failed to destroy superseded instance after cutover")
+	}
+
+	metrics.IncDisruption(reason)
+	c.recordEvent(r.pod, reason, fmt.Sprintf("Replaced instance %d with %d: %s", r.instance.ID, replacement.ID, detail))
+
+	c.mu.Lock()
+	c.records[r.pod.Name] = &launchRecord{
+		pod:        r.pod,
+		instance:   *replacement,
+		configHash: configHash(c.currentConfig(), r.pod),
+		launchedAt: time.Now(),
+	}
+	c.mu.Unlock()
+
+	log.Info("disrupted instance replaced", "replacementInstanceId", replacement.ID, "detail", detail)
+}
+
+func (c *Controller) recordEvent(pod *corev1.Pod, reason, message string) {
+	c.recorder.Event(&corev1.ObjectReference{
+		Kind:      "Pod",
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		UID:       pod.UID,
+	}, corev1.EventTypeNormal, reason, message)
+}
@@ -0,0 +1,41 @@
+package disruption
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/scheduler"
+)
+
+// configHash summarizes the scheduling criteria and onstart behavior a pod's
+// instance was launched with, so a later change to either can be detected as
+// drift without having to diff every field by hand.
+func configHash(config *scheduler.SchedulerConfig, pod *corev1.Pod) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "gpu=%s;minGpuGB=%d;maxPrice=%.4f;minReliability=%.4f;verifiedOnly=%t;",
+		config.GPUType, config.MinGPUMemoryGB, config.MaxPricePerHour, config.MinReliability, config.VerifiedOnly)
+	fmt.Fprintf(&b, "preferred=%s;fallback=%s;blocked=%s;", joinSorted(config.PreferredRegions), joinSorted(config.FallbackRegions), joinSorted(config.BlockedRegions))
+	fmt.Fprintf(&b, "onstart=%s;image=%s;", pod.Annotations["vast.ai/onstart"], podImage(pod))
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func joinSorted(items []string) string {
+	sorted := append([]string(nil), items...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func podImage(pod *corev1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	return pod.Spec.Containers[0].Image
+}
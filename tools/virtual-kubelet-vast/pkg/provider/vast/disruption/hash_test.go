@@ -0,0 +1,38 @@
+package disruption
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/scheduler"
+)
+
+func TestConfigHash_StableForEqualInputs(t *testing.T) {
+	config := &scheduler.SchedulerConfig{GPUType: "RTX_3060", MaxPricePerHour: 0.5, PreferredRegions: []string{"US", "CA"}}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "example/app:v1"}}}}
+
+	if configHash(config, pod) != configHash(config, pod) {
+		t.Fatal("configHash() is not stable for identical inputs")
+	}
+}
+
+func TestConfigHash_ChangesWithConfig(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "example/app:v1"}}}}
+
+	a := configHash(&scheduler.SchedulerConfig{MaxPricePerHour: 0.5}, pod)
+	b := configHash(&scheduler.SchedulerConfig{MaxPricePerHour: 0.75}, pod)
+	if a == b {
+		t.Fatal("configHash() unchanged after MaxPricePerHour changed, want different hash")
+	}
+}
+
+func TestConfigHash_RegionOrderDoesNotMatter(t *testing.T) {
+	pod := &corev1.Pod{}
+
+	a := configHash(&scheduler.SchedulerConfig{PreferredRegions: []string{"US", "CA"}}, pod)
+	b := configHash(&scheduler.SchedulerConfig{PreferredRegions: []string{"CA", "US"}}, pod)
+	if a != b {
+		t.Fatal("configHash() differs for reordered PreferredRegions, want equal")
+	}
+}
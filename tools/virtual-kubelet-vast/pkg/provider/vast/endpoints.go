@@ -3,9 +3,12 @@ package vast
 import (
 	"context"
 	"fmt"
+	"net"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -14,9 +17,35 @@ import (
 	vapi "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
 )
 
-// EndpointManager handles updating Kubernetes service endpoints for Vast.ai instances
+// DefaultDrainTimeout is how long a superseded endpoint stays in the slice,
+// marked terminating, before it's removed - giving in-flight requests time to
+// finish against the old instance while new traffic shifts to the replacement.
+const DefaultDrainTimeout = 30 * time.Second
+
+// managedService describes one service this provider backs and which of the
+// pod's mapped instance ports its traffic should be routed to.
+type managedService struct {
+	name         string
+	instancePort int
+	healthPath   string
+}
+
+// managedServices lists the services EndpointManager keeps in sync with
+// running Vast.ai instances.
+var managedServices = []managedService{
+	{name: "june-stt", instancePort: 8001, healthPath: "/healthz"},
+	{name: "june-tts", instancePort: 8000, healthPath: "/healthz"},
+}
+
+// EndpointManager keeps discovery.k8s.io/v1 EndpointSlices in sync with the
+// Vast.ai instance backing each pod. It gives each pod its own slice per
+// service and address family, named and owned by the pod, so a slice is
+// garbage-collected the moment its pod is - mirroring how external-dns shards
+// headless-service endpoints per workload rather than sharing one slice
+// across a whole service.
 type EndpointManager struct {
-	clientset kubernetes.Interface
+	clientset    kubernetes.Interface
+	drainTimeout time.Duration
 }
 
 // NewEndpointManager creates a new endpoint manager
@@ -35,89 +64,145 @@ func NewEndpointManager() *EndpointManager {
 	}
 
 	return &EndpointManager{
-		clientset: clientset,
+		clientset:    clientset,
+		drainTimeout: DefaultDrainTimeout,
 	}
 }
 
-// UpdatePodEndpoints updates the service endpoints for STT and TTS services
+// UpdatePodEndpoints points pod's service endpoints at instance. On first
+// call for a pod this creates its slices; called again for the same pod with
+// a different instance (a disruption-controller replacement), the new
+// instance is added as an additional, not-yet-ready endpoint while the old
+// one drains instead of being dropped immediately.
 func (e *EndpointManager) UpdatePodEndpoints(ctx context.Context, pod *corev1.Pod, instance *vapi.Instance) error {
 	if e.clientset == nil {
 		return fmt.Errorf("kubernetes client not available")
 	}
 
 	log := klog.FromContext(ctx)
-	log.Info(fmt.Sprintf("Updating service endpoints for pod %s (instance %d at %s)", 
+	log.Info(fmt.Sprintf("Updating service endpoints for pod %s (instance %d at %s)",
 		pod.Name, instance.ID, instance.PublicIP))
 
-	// Update june-stt service endpoints (port 8001)
-	sttExternalPort := instance.Ports[8001]
-	if sttExternalPort == 0 {
-		return fmt.Errorf("STT port 8001 not found in instance port mapping")
+	for _, svc := range managedServices {
+		port := instance.Ports[svc.instancePort]
+		if port == 0 {
+			return fmt.Errorf("%s port %d not found in instance port mapping", svc.name, svc.instancePort)
+		}
+
+		if err := e.upsertEndpoint(ctx, pod, instance, svc, port); err != nil {
+			return fmt.Errorf("failed to update %s endpoints: %w", svc.name, err)
+		}
 	}
 
-	if err := e.updateServiceEndpoint(ctx, "default", "june-stt", instance.PublicIP, sttExternalPort); err != nil {
-		return fmt.Errorf("failed to update june-stt endpoints: %w", err)
+	log.Info(fmt.Sprintf("Service endpoints updated for pod %s, instance %d", pod.Name, instance.ID))
+	return nil
+}
+
+// upsertEndpoint adds or replaces pod's endpoint for svc in the EndpointSlice
+// addressed to instance's IP family, then kicks off a background health probe
+// to flip the new endpoint ready once it's actually serving traffic.
+func (e *EndpointManager) upsertEndpoint(ctx context.Context, pod *corev1.Pod, instance *vapi.Instance, svc managedService, port int) error {
+	log := klog.FromContext(ctx)
+	family := addressFamily(instance.PublicIP)
+	sliceName := podSliceName(pod, svc.name, family)
+
+	slice, err := e.getOrCreateSlice(ctx, pod, sliceName, svc.name, family, int32(port))
+	if err != nil {
+		return err
 	}
 
-	// Update june-tts service endpoints (port 8000)
-	ttsExternalPort := instance.Ports[8000]
-	if ttsExternalPort == 0 {
-		return fmt.Errorf("TTS port 8000 not found in instance port mapping")
+	generation := podInstanceKey(pod, instance)
+	newEndpoint := discoveryv1.Endpoint{
+		Addresses: []string{instance.PublicIP},
+		Hostname:  ptrString(generation),
+		Conditions: discoveryv1.EndpointConditions{
+			Ready:       ptrBool(false),
+			Serving:     ptrBool(true),
+			Terminating: ptrBool(false),
+		},
+		TargetRef: &corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+		},
 	}
 
-	if err := e.updateServiceEndpoint(ctx, "default", "june-tts", instance.PublicIP, ttsExternalPort); err != nil {
-		return fmt.Errorf("failed to update june-tts endpoints: %w", err)
+	endpoints := make([]discoveryv1.Endpoint, 0, len(slice.Endpoints)+1)
+	var draining []discoveryv1.Endpoint
+	for _, ep := range slice.Endpoints {
+		if ep.Hostname != nil && *ep.Hostname == generation {
+			continue // superseded below by the fresh copy
+		}
+		ep.Conditions.Terminating = ptrBool(true)
+		draining = append(draining, ep)
 	}
+	endpoints = append(endpoints, newEndpoint)
+	endpoints = append(endpoints, draining...)
+	slice.Endpoints = endpoints
 
-	log.Info(fmt.Sprintf("Service endpoints updated: june-stt → %s:%d, june-tts → %s:%d",
-		instance.PublicIP, sttExternalPort, instance.PublicIP, ttsExternalPort))
+	if _, err := e.clientset.DiscoveryV1().EndpointSlices(pod.Namespace).Update(ctx, slice, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update EndpointSlice %s: %w", sliceName, err)
+	}
+
+	for _, old := range draining {
+		go e.scheduleDrain(klog.NewContext(context.Background(), log), pod.Namespace, sliceName, *old.Hostname)
+	}
+
+	go e.probeUntilReady(klog.NewContext(context.Background(), log), pod.Namespace, sliceName, generation, instance.PublicIP, port, svc.healthPath)
 
 	return nil
 }
 
-// updateServiceEndpoint updates a specific service endpoint
-func (e *EndpointManager) updateServiceEndpoint(ctx context.Context, namespace, serviceName, ip string, port int) error {
-	// Create or update endpoints
-	endpoints := &corev1.Endpoints{
+// getOrCreateSlice fetches pod's existing slice for service/family, or
+// creates an empty one owned by pod if none exists yet.
+func (e *EndpointManager) getOrCreateSlice(ctx context.Context, pod *corev1.Pod, sliceName, serviceName string, family discoveryv1.AddressType, port int32) (*discoveryv1.EndpointSlice, error) {
+	slice, err := e.clientset.DiscoveryV1().EndpointSlices(pod.Namespace).Get(ctx, sliceName, metav1.GetOptions{})
+	if err == nil {
+		return slice, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get EndpointSlice %s: %w", sliceName, err)
+	}
+
+	slice = &discoveryv1.EndpointSlice{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      serviceName,
-			Namespace: namespace,
-			Annotations: map[string]string{
-				"vast.ai/managed":                "true",
-				"virtual-kubelet.io/last-update": metav1.Now().Format(time.RFC3339),
+			Name:      sliceName,
+			Namespace: pod.Namespace,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: serviceName,
+				discoveryv1.LabelManagedBy:   "vast.ai-virtual-kubelet",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         "v1",
+					Kind:               "Pod",
+					Name:               pod.Name,
+					UID:                pod.UID,
+					Controller:         ptrBool(true),
+					BlockOwnerDeletion: ptrBool(true),
+				},
 			},
 		},
-		Subsets: []corev1.EndpointSubset{
+		AddressType: family,
+		Ports: []discoveryv1.EndpointPort{
 			{
-				Addresses: []corev1.EndpointAddress{
-					{
-						IP: ip,
-					},
-				},
-				Ports: []corev1.EndpointPort{
-					{
-						Port: int32(port),
-						Protocol: corev1.ProtocolTCP,
-					},
-				},
+				Port:     &port,
+				Protocol: protoPtr(corev1.ProtocolTCP),
 			},
 		},
 	}
 
-	// Try to update existing endpoints first
-	_, err := e.clientset.CoreV1().Endpoints(namespace).Update(ctx, endpoints, metav1.UpdateOptions{})
+	created, err := e.clientset.DiscoveryV1().EndpointSlices(pod.Namespace).Create(ctx, slice, metav1.CreateOptions{})
 	if err != nil {
-		// If update fails, try to create
-		_, err = e.clientset.CoreV1().Endpoints(namespace).Create(ctx, endpoints, metav1.CreateOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to create/update endpoints: %w", err)
-		}
+		return nil, fmt.Errorf("failed to create EndpointSlice %s: %w", sliceName, err)
 	}
-
-	return nil
+	return created, nil
 }
 
-// CleanupPodEndpoints removes endpoints when pod is deleted
+// CleanupPodEndpoints deletes pod's EndpointSlices when the pod is deleted.
+// No drain is needed here: the pod is already gone, so there's no "shift
+// traffic to a replacement first" to do.
 func (e *EndpointManager) CleanupPodEndpoints(ctx context.Context, pod *corev1.Pod) error {
 	if e.clientset == nil {
 		return nil
@@ -126,22 +211,46 @@ func (e *EndpointManager) CleanupPodEndpoints(ctx context.Context, pod *corev1.P
 	log := klog.FromContext(ctx)
 	log.Info(fmt.Sprintf("Cleaning up service endpoints for pod %s", pod.Name))
 
-	// Remove endpoints by setting empty subsets
-	services := []string{"june-stt", "june-tts"}
-	for _, serviceName := range services {
-		endpoints, err := e.clientset.CoreV1().Endpoints("default").Get(ctx, serviceName, metav1.GetOptions{})
-		if err != nil {
-			continue // Service might not exist
-		}
-
-		// Clear subsets
-		endpoints.Subsets = []corev1.EndpointSubset{}
-		
-		_, err = e.clientset.CoreV1().Endpoints("default").Update(ctx, endpoints, metav1.UpdateOptions{})
-		if err != nil {
-			log.Info(fmt.Sprintf("Failed to cleanup %s endpoints: %v", serviceName, err))
+	for _, svc := range managedServices {
+		for _, family := range []discoveryv1.AddressType{discoveryv1.AddressTypeIPv4, discoveryv1.AddressTypeIPv6} {
+			sliceName := podSliceName(pod, svc.name, family)
+			err := e.clientset.DiscoveryV1().EndpointSlices(pod.Namespace).Delete(ctx, sliceName, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				log.Info(fmt.Sprintf("Failed to delete EndpointSlice %s: %v", sliceName, err))
+			}
 		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// podSliceName derives the deterministic per-pod, per-service, per-family
+// slice name, so repeated calls for the same pod find the same slice.
+func podSliceName(pod *corev1.Pod, service string, family discoveryv1.AddressType) string {
+	suffix := "ipv4"
+	if family == discoveryv1.AddressTypeIPv6 {
+		suffix = "ipv6"
+	}
+	return fmt.Sprintf("%s-%s-%s", service, pod.Name, suffix)
+}
+
+// podInstanceKey identifies one (pod, instance) generation, distinguishing a
+// replacement instance's endpoint from the one it's superseding while both
+// are briefly present in the same slice.
+func podInstanceKey(pod *corev1.Pod, instance *vapi.Instance) string {
+	return fmt.Sprintf("%s.%d", pod.Name, instance.ID)
+}
+
+// addressFamily reports the EndpointSlice address type for ip.
+func addressFamily(ip string) discoveryv1.AddressType {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return discoveryv1.AddressTypeIPv6
+	}
+	return discoveryv1.AddressTypeIPv4
+}
+
+func ptrBool(b bool) *bool { return &b }
+
+func ptrString(s string) *string { return &s }
+
+func protoPtr(p corev1.Protocol) *corev1.Protocol { return &p }
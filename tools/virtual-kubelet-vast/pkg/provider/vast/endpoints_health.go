@@ -0,0 +1,149 @@
+package vast
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// DefaultHealthCheckInterval is how often probeUntilReady polls a new
+// endpoint's health path before marking it ready.
+const DefaultHealthCheckInterval = 2 * time.Second
+
+// DefaultHealthCheckTimeout bounds each individual probe request.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// MaxProbeWait is how long probeUntilReady keeps retrying before giving up on
+// an endpoint; past this point the instance is assumed stuck and is left
+// not-ready rather than probed forever.
+const MaxProbeWait = 5 * time.Minute
+
+// probeUntilReady polls http://ip:port/healthPath until it succeeds or
+// MaxProbeWait elapses, then patches the slice's matching endpoint (by
+// generation, the pod+instance key set as its Hostname) to Ready accordingly.
+func (e *EndpointManager) probeUntilReady(ctx context.Context, namespace, sliceName, generation, ip string, port int, healthPath string) {
+	log := klog.FromContext(ctx).WithValues("slice", sliceName, "endpoint", generation)
+	client := &http.Client{Timeout: DefaultHealthCheckTimeout}
+	url := fmt.Sprintf("http://%s:%d%s", ip, port, healthPath)
+
+	deadline := time.Now().Add(MaxProbeWait)
+	ticker := time.NewTicker(DefaultHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if probeOnce(ctx, client, url) {
+			if err := e.setEndpointReady(ctx, namespace, sliceName, generation, true); err != nil {
+				log.Error(err, "failed to mark endpoint ready after successful health probe")
+			}
+			return
+		}
+
+		if time.Now().After(deadline) {
+			log.Info("giving up on health probe, leaving endpoint not ready", "url", url, "waited", MaxProbeWait)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeOnce reports whether a GET against url returned a 2xx status.
+func probeOnce(ctx context.Context, client *http.Client, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// scheduleDrain waits drainTimeout, then removes the endpoint identified by
+// generation from the slice - giving requests already routed to the
+// superseded instance time to finish before it disappears from DNS/kube-proxy.
+func (e *EndpointManager) scheduleDrain(ctx context.Context, namespace, sliceName, generation string) {
+	log := klog.FromContext(ctx).WithValues("slice", sliceName, "endpoint", generation)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(e.drainTimeout):
+	}
+
+	if err := e.removeEndpoint(ctx, namespace, sliceName, generation); err != nil {
+		log.Error(err, "failed to remove drained endpoint")
+	}
+}
+
+// setEndpointReady patches the endpoint identified by generation to ready.
+func (e *EndpointManager) setEndpointReady(ctx context.Context, namespace, sliceName, generation string, ready bool) error {
+	slice, err := e.clientset.DiscoveryV1().EndpointSlices(namespace).Get(ctx, sliceName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil // slice (and the pod that owned it) is already gone
+		}
+		return fmt.Errorf("failed to get EndpointSlice %s: %w", sliceName, err)
+	}
+
+	found := false
+	for i, ep := range slice.Endpoints {
+		if ep.Hostname != nil && *ep.Hostname == generation {
+			slice.Endpoints[i].Conditions.Ready = ptrBool(ready)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil // endpoint was already removed, e.g. the pod was deleted mid-probe
+	}
+
+	_, err = e.clientset.DiscoveryV1().EndpointSlices(namespace).Update(ctx, slice, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update EndpointSlice %s: %w", sliceName, err)
+	}
+	return nil
+}
+
+// removeEndpoint drops the endpoint identified by generation from the slice.
+func (e *EndpointManager) removeEndpoint(ctx context.Context, namespace, sliceName, generation string) error {
+	slice, err := e.clientset.DiscoveryV1().EndpointSlices(namespace).Get(ctx, sliceName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get EndpointSlice %s: %w", sliceName, err)
+	}
+
+	remaining := make([]discoveryv1.Endpoint, 0, len(slice.Endpoints))
+	for _, ep := range slice.Endpoints {
+		if ep.Hostname != nil && *ep.Hostname == generation {
+			continue
+		}
+		remaining = append(remaining, ep)
+	}
+	if len(remaining) == len(slice.Endpoints) {
+		return nil // already removed
+	}
+	slice.Endpoints = remaining
+
+	_, err = e.clientset.DiscoveryV1().EndpointSlices(namespace).Update(ctx, slice, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update EndpointSlice %s: %w", sliceName, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package latency
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL bounds how long a measurement is trusted before the next
+// search re-probes its offer.
+const DefaultCacheTTL = 2 * time.Minute
+
+// DefaultCacheSize is how many hosts' measurements CachingProvider keeps
+// before evicting the least recently used.
+const DefaultCacheSize = 512
+
+type cacheEntry struct {
+	hostID  string
+	rtt     time.Duration
+	expires time.Time
+}
+
+// CachingProvider wraps a LatencyProvider with an in-memory LRU+TTL cache
+// keyed by hostID, so repeated searches for the same host don't re-probe a
+// latency that was measured moments ago.
+type CachingProvider struct {
+	inner   LatencyProvider
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front = most recently used
+}
+
+// NewCachingProvider wraps inner with an LRU+TTL cache of at most maxSize
+// entries, each valid for ttl.
+func NewCachingProvider(inner LatencyProvider, maxSize int, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{
+		inner:   inner,
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Measure returns the cached measurement for hostID if it's still fresh,
+// otherwise probes via inner and caches the result.
+func (c *CachingProvider) Measure(ctx context.Context, hostID string, target Target) (time.Duration, error) {
+	if rtt, ok := c.get(hostID); ok {
+		return rtt, nil
+	}
+
+	rtt, err := c.inner.Measure(ctx, hostID, target)
+	if err != nil {
+		return 0, err
+	}
+
+	c.put(hostID, rtt)
+	return rtt, nil
+}
+
+func (c *CachingProvider) get(hostID string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[hostID]
+	if !ok {
+		return 0, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, hostID)
+		return 0, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.rtt, true
+}
+
+func (c *CachingProvider) put(hostID string, rtt time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[hostID]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.rtt = rtt
+		entry.expires = time.Now().Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&cacheEntry{hostID: hostID, rtt: rtt, expires: time.Now().Add(c.ttl)})
+	c.entries[hostID] = elem
+
+	if c.maxSize > 0 && c.order.Len() > c.maxSize {
+		if oldest := c.order.Back(); oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).hostID)
+		}
+	}
+}
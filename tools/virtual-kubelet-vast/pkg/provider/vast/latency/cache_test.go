@@ -0,0 +1,68 @@
+package latency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	calls int
+	rtt   time.Duration
+}
+
+func (f *fakeProvider) Measure(context.Context, string, Target) (time.Duration, error) {
+	f.calls++
+	return f.rtt, nil
+}
+
+func TestCachingProvider_CachesWithinTTL(t *testing.T) {
+	inner := &fakeProvider{rtt: 10 * time.Millisecond}
+	c := NewCachingProvider(inner, DefaultCacheSize, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		rtt, err := c.Measure(context.Background(), "host-1", Target{Host: "1.2.3.4", Port: 22})
+		if err != nil {
+			t.Fatalf("Measure() error = %v", err)
+		}
+		if rtt != 10*time.Millisecond {
+			t.Errorf("Measure() = %v, want 10ms", rtt)
+		}
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("inner.calls = %d, want 1 (subsequent calls should hit cache)", inner.calls)
+	}
+}
+
+func TestCachingProvider_ReprobesAfterTTLExpires(t *testing.T) {
+	inner := &fakeProvider{rtt: 10 * time.Millisecond}
+	c := NewCachingProvider(inner, DefaultCacheSize, time.Nanosecond)
+
+	if _, err := c.Measure(context.Background(), "host-1", Target{}); err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := c.Measure(context.Background(), "host-1", Target{}); err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("inner.calls = %d, want 2 (entry should have expired)", inner.calls)
+	}
+}
+
+func TestCachingProvider_EvictsLeastRecentlyUsed(t *testing.T) {
+	inner := &fakeProvider{rtt: 10 * time.Millisecond}
+	c := NewCachingProvider(inner, 2, time.Minute)
+
+	c.Measure(context.Background(), "host-1", Target{})
+	c.Measure(context.Background(), "host-2", Target{})
+	c.Measure(context.Background(), "host-3", Target{}) // evicts host-1
+
+	inner.calls = 0
+	c.Measure(context.Background(), "host-1", Target{})
+	if inner.calls != 1 {
+		t.Errorf("host-1 should have been evicted and re-probed, inner.calls = %d, want 1", inner.calls)
+	}
+}
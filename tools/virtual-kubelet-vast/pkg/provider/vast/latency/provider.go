@@ -0,0 +1,23 @@
+// Package latency measures and caches round-trip latency to candidate
+// Vast.ai offers, so the scheduler can filter out offers the configured
+// MaxLatencyMS wouldn't tolerate and score the rest by how fast they
+// actually are to reach, rather than trusting geolocation as a proxy for
+// network distance.
+package latency
+
+import (
+	"context"
+	"time"
+)
+
+// Target is the host:port a LatencyProvider measures round-trip time to.
+type Target struct {
+	Host string
+	Port int
+}
+
+// LatencyProvider measures round-trip latency to a candidate offer. hostID
+// identifies the offer for caching; implementations are free to ignore it.
+type LatencyProvider interface {
+	Measure(ctx context.Context, hostID string, target Target) (time.Duration, error)
+}
@@ -0,0 +1,37 @@
+package latency
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RemoteAgentClient probes latency from a node-local agent's network
+// position rather than the virtual-kubelet control plane's, so measurements
+// reflect the path real traffic will actually take. It's expressed as a
+// narrow interface rather than a concrete gRPC client so this package doesn't
+// need to depend on generated protobuf code; wire in an implementation that
+// dials the DaemonSet-side agent to enable it.
+type RemoteAgentClient interface {
+	Probe(ctx context.Context, target Target) (time.Duration, error)
+}
+
+// RemoteAgentProvider measures latency by delegating to a RemoteAgentClient
+// running on the node handling the pod's traffic, instead of probing from
+// wherever the scheduler process happens to run.
+type RemoteAgentProvider struct {
+	client RemoteAgentClient
+}
+
+// NewRemoteAgentProvider wraps client as a LatencyProvider.
+func NewRemoteAgentProvider(client RemoteAgentClient) *RemoteAgentProvider {
+	return &RemoteAgentProvider{client: client}
+}
+
+// Measure delegates to the configured RemoteAgentClient.
+func (p *RemoteAgentProvider) Measure(ctx context.Context, _ string, target Target) (time.Duration, error) {
+	if p.client == nil {
+		return 0, fmt.Errorf("no remote agent client configured")
+	}
+	return p.client.Probe(ctx, target)
+}
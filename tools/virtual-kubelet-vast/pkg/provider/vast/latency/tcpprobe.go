@@ -0,0 +1,45 @@
+package latency
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultProbeTimeout bounds a single TCP dial.
+const DefaultProbeTimeout = 1500 * time.Millisecond
+
+// TCPProbeProvider measures latency as the time to complete a TCP handshake
+// against the target port. It doesn't send or receive any application data,
+// so it measures the same thing whether the port speaks SSH, HTTP, or
+// nothing at all behind the accept.
+type TCPProbeProvider struct {
+	Timeout time.Duration
+}
+
+// NewTCPProbeProvider creates a TCPProbeProvider with DefaultProbeTimeout.
+func NewTCPProbeProvider() *TCPProbeProvider {
+	return &TCPProbeProvider{Timeout: DefaultProbeTimeout}
+}
+
+// Measure dials target and times how long the handshake takes.
+func (p *TCPProbeProvider) Measure(ctx context.Context, _ string, target Target) (time.Duration, error) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	addr := fmt.Sprintf("%s:%d", target.Host, target.Port)
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, fmt.Errorf("tcp probe to %s: %w", addr, err)
+	}
+	rtt := time.Since(start)
+	conn.Close()
+
+	return rtt, nil
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// decisionLogSize bounds the in-process ring buffer DebugSchedulingHandler
+// serves; it's a post-mortem aid, not a durable audit log, so a modest size
+// keeps memory use flat under steady scheduling traffic.
+const decisionLogSize = 50
+
+// CandidateSummary is one scored offer considered for a scheduling decision,
+// mirroring the "Candidate N: Instance X, Score ..." line selectBestInstance
+// already logs for the top 3.
+type CandidateSummary struct {
+	InstanceID   int      `json:"instanceId"`
+	Score        float64  `json:"score"`
+	Region       string   `json:"region"`
+	PricePerHour float64  `json:"pricePerHour"`
+	Notes        []string `json:"notes,omitempty"`
+}
+
+// SchedulingDecision records the outcome of one selectBestInstance call: the
+// pod it was choosing for and the top candidates it weighed, so an operator
+// can reconstruct after the fact why a particular offer won (or why nothing
+// did).
+type SchedulingDecision struct {
+	Time       time.Time          `json:"time"`
+	Pod        string             `json:"pod"` // "<namespace>/<name>"
+	Profile    string             `json:"profile"`
+	Candidates []CandidateSummary `json:"candidates"`
+	Chosen     int                `json:"chosen"` // winning candidate's InstanceID, 0 if none fit
+	Reason     string             `json:"reason,omitempty"`
+}
+
+var decisionLog = struct {
+	mu      sync.Mutex
+	entries []SchedulingDecision
+}{}
+
+// RecordDecision appends d to the ring buffer, evicting the oldest entry once
+// decisionLogSize is exceeded.
+func RecordDecision(d SchedulingDecision) {
+	decisionLog.mu.Lock()
+	defer decisionLog.mu.Unlock()
+
+	decisionLog.entries = append(decisionLog.entries, d)
+	if over := len(decisionLog.entries) - decisionLogSize; over > 0 {
+		decisionLog.entries = decisionLog.entries[over:]
+	}
+}
+
+// RecentDecisions returns a snapshot of the ring buffer, oldest first.
+func RecentDecisions() []SchedulingDecision {
+	decisionLog.mu.Lock()
+	defer decisionLog.mu.Unlock()
+
+	out := make([]SchedulingDecision, len(decisionLog.entries))
+	copy(out, decisionLog.entries)
+	return out
+}
+
+// DebugSchedulingHandler serves the last decisionLogSize scheduling decisions
+// as JSON, for an operator to inspect why the scheduler placed (or failed to
+// place) a pod without needing a metrics backend.
+func DebugSchedulingHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(RecentDecisions()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
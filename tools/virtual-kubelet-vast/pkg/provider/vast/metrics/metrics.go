@@ -0,0 +1,125 @@
+// Package metrics is the shared Prometheus metrics subsystem for the Vast.ai
+// provider's scheduling, endpoint propagation, and disruption paths. It's a
+// leaf package - scheduler, disruption, and the provider itself all import
+// it - so it must not import any of them back.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// durationLabels is shared by every response-time histogram below: the
+	// outcome of the operation being timed ("success"/"error"/"timeout",
+	// operation-dependent), the GPU type requested, and the region the offer
+	// or instance landed in. Modeled on antrea's scale-test response-time
+	// framework, which buckets the same way per network operation.
+	durationLabels = []string{"outcome", "gpu_type", "region"}
+
+	searchDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vast_scheduler_search_duration_seconds",
+		Help:    "Time spent searching Vast.ai for candidate offers, labeled by outcome, GPU type, and region.",
+		Buckets: prometheus.DefBuckets,
+	}, durationLabels)
+
+	scoringDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vast_scheduler_scoring_duration_seconds",
+		Help:    "Time spent running predicates and priorities over candidate offers, labeled by outcome, GPU type, and region.",
+		Buckets: prometheus.DefBuckets,
+	}, durationLabels)
+
+	createInstanceDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vast_scheduler_create_instance_duration_seconds",
+		Help:    "Time spent launching the chosen offer as a Vast.ai instance, labeled by outcome, GPU type, and region.",
+		Buckets: prometheus.DefBuckets,
+	}, durationLabels)
+
+	endpointPropagationDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vast_scheduler_endpoint_propagation_duration_seconds",
+		Help:    "Time spent updating Service endpoints to point at a newly launched instance, labeled by outcome, GPU type, and region.",
+		Buckets: prometheus.DefBuckets,
+	}, durationLabels)
+
+	podToReadyDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vast_scheduler_pod_to_ready_duration_seconds",
+		Help:    "End-to-end time from instance launch to a wait-ready pod passing its readinessProbe, labeled by outcome, GPU type, and region.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12), // 1s .. ~34min, ready waits run much longer than API calls
+	}, durationLabels)
+
+	offersFilteredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vast_scheduler_offers_filtered_total",
+		Help: "Total number of offers rejected by each predicate, labeled by predicate name.",
+	}, []string{"predicate"})
+
+	disruptionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vast_scheduler_disruptions_total",
+		Help: "Total number of instances replaced by the disruption controller, labeled by reason (Expired, Drifted, Consolidation).",
+	}, []string{"reason"})
+
+	namespaceHourlySpend = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vast_scheduler_namespace_hourly_spend_dollars",
+		Help: "Combined $/hr of running instances backing pods in each namespace, sampled opportunistically whenever MaxTotalSpendPerHour is checked.",
+	}, []string{"namespace"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		searchDurationSeconds,
+		scoringDurationSeconds,
+		createInstanceDurationSeconds,
+		endpointPropagationDurationSeconds,
+		podToReadyDurationSeconds,
+		offersFilteredTotal,
+		disruptionsTotal,
+		namespaceHourlySpend,
+	)
+}
+
+// ObserveSearchDuration records how long a Vast.ai offer search took.
+func ObserveSearchDuration(outcome, gpuType, region string, start time.Time) {
+	searchDurationSeconds.WithLabelValues(outcome, gpuType, region).Observe(time.Since(start).Seconds())
+}
+
+// ObserveScoringDuration records how long predicates+priorities took to pick
+// a winning offer out of the search results.
+func ObserveScoringDuration(outcome, gpuType, region string, start time.Time) {
+	scoringDurationSeconds.WithLabelValues(outcome, gpuType, region).Observe(time.Since(start).Seconds())
+}
+
+// ObserveCreateInstanceDuration records how long Vast.ai took to launch the
+// chosen offer as a running instance.
+func ObserveCreateInstanceDuration(outcome, gpuType, region string, start time.Time) {
+	createInstanceDurationSeconds.WithLabelValues(outcome, gpuType, region).Observe(time.Since(start).Seconds())
+}
+
+// ObserveEndpointPropagationDuration records how long it took to point a
+// pod's Service endpoints at its newly launched instance.
+func ObserveEndpointPropagationDuration(outcome, gpuType, region string, start time.Time) {
+	endpointPropagationDurationSeconds.WithLabelValues(outcome, gpuType, region).Observe(time.Since(start).Seconds())
+}
+
+// ObservePodToReadyDuration records the end-to-end time a wait-ready pod
+// spent waiting for its instance to report Running and pass its
+// readinessProbe.
+func ObservePodToReadyDuration(outcome, gpuType, region string, start time.Time) {
+	podToReadyDurationSeconds.WithLabelValues(outcome, gpuType, region).Observe(time.Since(start).Seconds())
+}
+
+// IncOffersFiltered records that predicate rejected one offer.
+func IncOffersFiltered(predicate string) {
+	offersFilteredTotal.WithLabelValues(predicate).Inc()
+}
+
+// IncDisruption records that the disruption controller replaced an instance
+// for reason (Expired, Drifted, or Consolidation).
+func IncDisruption(reason string) {
+	disruptionsTotal.WithLabelValues(reason).Inc()
+}
+
+// SetNamespaceHourlySpend sets namespace's current combined running-instance
+// $/hr to dph.
+func SetNamespaceHourlySpend(namespace string, dph float64) {
+	namespaceHourlySpend.WithLabelValues(namespace).Set(dph)
+}
@@ -0,0 +1,70 @@
+// Package nodepool loads the multi-region / multi-GPU-class node pool config that
+// replaces the single hard-coded vk-vast node.
+package nodepool
+
+import (
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// NodeConfig declares one virtual node: its identity, scheduling criteria, and the
+// labels/taints advertised on the corresponding Kubernetes Node object.
+type NodeConfig struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Taints []corev1.Taint    `json:"taints,omitempty"`
+
+	GPUType          string   `json:"gpuType"`
+	MinGPUMemoryGB   int      `json:"minGPUMemoryGB"`
+	MaxPricePerHour  float64  `json:"maxPricePerHour"`
+	MinReliability   float64  `json:"minReliability"`
+	MinDownloadMbps  int      `json:"minDownloadMbps"`
+	MinUploadMbps    int      `json:"minUploadMbps"`
+	VerifiedOnly     bool     `json:"verifiedOnly"`
+	PreferredRegions []string `json:"preferredRegions,omitempty"`
+	FallbackRegions  []string `json:"fallbackRegions,omitempty"`
+	BlockedRegions   []string `json:"blockedRegions,omitempty"`
+
+	MaxLatencyMS        int     `json:"maxLatencyMS"`
+	LatencyCheckEnabled bool    `json:"latencyCheckEnabled"`
+	MinScoreThreshold   float64 `json:"minScoreThreshold"`
+}
+
+// PoolConfig is the top-level VAST_POOL_CONFIG document: a list of virtual nodes,
+// each run by its own node.NodeController in main.go.
+type PoolConfig struct {
+	Nodes []NodeConfig `json:"nodes"`
+}
+
+// Load reads and parses the pool config at path. An empty path is not valid; callers
+// should fall back to a single hard-coded node when VAST_POOL_CONFIG is unset.
+func Load(path string) (*PoolConfig, error) {
+	if path == "" {
+		return nil, fmt.Errorf("node pool config path is empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read node pool config %s: %w", path, err)
+	}
+
+	var cfg PoolConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse node pool config %s: %w", path, err)
+	}
+
+	if len(cfg.Nodes) == 0 {
+		return nil, fmt.Errorf("node pool config %s declares no nodes", path)
+	}
+
+	for i, n := range cfg.Nodes {
+		if n.Name == "" {
+			return nil, fmt.Errorf("node pool config %s: node at index %d has no name", path, i)
+		}
+	}
+
+	return &cfg, nil
+}
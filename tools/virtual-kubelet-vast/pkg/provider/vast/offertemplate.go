@@ -0,0 +1,78 @@
+package vast
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	vastv1alpha1 "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/apis/vast/v1alpha1"
+	vapi "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+// offerTemplateResolver implements scheduler.OfferTemplateProvider by
+// fetching VastOfferTemplate resources directly from the apiserver on every
+// call, rather than through a shared informer cache: the scheduler only ever
+// resolves a template once per CreatePod, so a direct Get matches the rest of
+// this provider's list-and-join style (see reconciler.go, preempt.go) better
+// than the overhead of a long-lived watch.
+type offerTemplateResolver struct {
+	client *vastv1alpha1.Clientset
+}
+
+// newOfferTemplateResolver builds a resolver from in-cluster config, mirroring
+// NewEndpointManager's bootstrapping. A failure to build the client disables
+// offer templates rather than failing provider startup - the same tolerance
+// NewEndpointManager applies to its own Kubernetes client.
+func newOfferTemplateResolver() *offerTemplateResolver {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		klog.Error(fmt.Errorf("failed to create Kubernetes config for offer template resolver: %w", err))
+		return &offerTemplateResolver{}
+	}
+
+	client, err := vastv1alpha1.NewForConfig(config)
+	if err != nil {
+		klog.Error(fmt.Errorf("failed to create VastOfferTemplate client: %w", err))
+		return &offerTemplateResolver{}
+	}
+
+	return &offerTemplateResolver{client: client}
+}
+
+func (r *offerTemplateResolver) Get(ctx context.Context, namespace, name string) (*vastv1alpha1.VastOfferTemplateSpec, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("offer template resolver not initialized")
+	}
+
+	template, err := r.client.VastOfferTemplates(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VastOfferTemplate %s/%s: %w", namespace, name, err)
+	}
+	return &template.Spec, nil
+}
+
+func (r *offerTemplateResolver) RecordMatch(ctx context.Context, namespace, name string, pod *corev1.Pod, offer vapi.InstanceOffer) error {
+	if r.client == nil {
+		return fmt.Errorf("offer template resolver not initialized")
+	}
+
+	template, err := r.client.VastOfferTemplates(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get VastOfferTemplate %s/%s: %w", namespace, name, err)
+	}
+
+	template.Status = vastv1alpha1.VastOfferTemplateStatus{
+		LastMatchedOfferID:      offer.ID,
+		LastMatchedPricePerHour: offer.DPH,
+		LastMatchedGPUModel:     offer.GPUName,
+		LastMatchedPod:          pod.Namespace + "/" + pod.Name,
+		LastMatchTime:           metav1.Now(),
+	}
+
+	_, err = r.client.VastOfferTemplates(namespace).UpdateStatus(ctx, template, metav1.UpdateOptions{})
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,34 +14,52 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	vapi "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/disruption"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/metrics"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/scheduler"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/stats"
 )
 
 // Summary for stats (simplified to avoid kubelet internals)
 type Summary struct {
-	Node *NodeStats `json:"node"`
+	Node *NodeStats       `json:"node"`
+	Pods []stats.PodStats `json:"pods"`
 }
 
 type NodeStats struct {
-	NodeName  string `json:"nodeName"`
-	StartTime string `json:"startTime"`
+	NodeName  string             `json:"nodeName"`
+	StartTime string             `json:"startTime"`
+	CPU       stats.CPUStats     `json:"cpu"`
+	Memory    stats.MemoryStats  `json:"memory"`
+	Network   stats.NetworkStats `json:"network"`
+	Fs        stats.FsStats      `json:"fs"`
 }
 
 type VastProvider struct {
-	client     *vapi.VastClient
-	nodeName   string
-	instances  map[string]*vapi.Instance  // podName -> instance
-	mu         sync.RWMutex
-	scheduler  *InstanceScheduler
-	endpoints  *EndpointManager
+	client         *vapi.VastClient
+	kubeClient     kubernetes.Interface
+	nodeName       string
+	instances      map[string]*vapi.Instance    // podName -> instance
+	failedPods     map[string]*corev1.PodStatus // podName -> status, for pods waitUntilHealthy gave up on
+	mu             sync.RWMutex
+	scheduler      *scheduler.InstanceScheduler
+	endpoints      *EndpointManager
+	disruption     *disruption.Controller
+	statusMgr      *statusManager
+	statsCollector *stats.Collector
+	stateRecon     *instanceStateReconciler
+	readyTimeout   time.Duration // see SetReadyTimeout; 0 means DefaultReadyTimeout
 }
 
-// NewVastProvider creates a new Vast.ai provider
-func NewVastProvider(ctx context.Context, apiKey, nodeName string) (*VastProvider, error) {
+// NewVastProvider creates a new Vast.ai provider. kubeClient is used to resolve
+// ConfigMap/Secret references and imagePullSecrets when translating pods.
+func NewVastProvider(ctx context.Context, apiKey, nodeName string, kubeClient kubernetes.Interface) (*VastProvider, error) {
 	log := pkglog.G(ctx).WithField("provider", "vast.ai")
-	
-	client, err := vapi.NewVastClient(apiKey)
+
+	client, err := vapi.NewVastClient(apiKey, kubeClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Vast.ai client: %w", err)
 	}
@@ -53,20 +72,69 @@ func NewVastProvider(ctx context.Context, apiKey, nodeName string) (*VastProvide
 	log.Info("Successfully connected to Vast.ai API")
 
 	p := &VastProvider{
-		client:    client,
-		nodeName:  nodeName,
-		instances: make(map[string]*vapi.Instance),
+		client:     client,
+		kubeClient: kubeClient,
+		nodeName:   nodeName,
+		instances:  make(map[string]*vapi.Instance),
+		failedPods: make(map[string]*corev1.PodStatus),
 	}
 
 	// Initialize scheduler
-	p.scheduler = NewInstanceScheduler(client)
-	
+	p.scheduler = scheduler.NewInstanceScheduler(client)
+
 	// Initialize endpoint manager
 	p.endpoints = NewEndpointManager()
 
+	// Initialize status manager (event-driven pod status updates)
+	p.statusMgr = newStatusManager(client)
+
+	// Initialize the stats collector (GPU/cgroup stats over SSH, for GetStatsSummary)
+	p.statsCollector = stats.NewCollector(client)
+
+	// Wire preemption: the scheduler can evict lower-priority instances'
+	// endpoints and pods once EnablePreemption/SetEndpointDrainer are called.
+	p.scheduler.EnablePreemption(kubeClient, nodeName)
+	p.scheduler.SetEndpointDrainer(p.endpoints)
+
+	// Let pods opt into a VastOfferTemplate's search criteria via annotation.
+	p.scheduler.SetOfferTemplateProvider(newOfferTemplateResolver())
+
+	// Initialize disruption controller
+	p.disruption = disruption.NewController(client, p.scheduler, p.endpoints, kubeClient, nodeName, disruption.DefaultConfig())
+
+	// Recovers p.instances across restarts and corrects drift if Vast.ai
+	// destroys an instance out-of-band; see StartStateReconciler.
+	p.stateRecon = newInstanceStateReconciler(p, client, kubeClient, nodeName)
+
 	return p, nil
 }
 
+// SetSchedulerConfig overrides this provider's instance-selection criteria, used by
+// the multi-region node pool to give each virtual node its own GPU class and region.
+func (p *VastProvider) SetSchedulerConfig(config *scheduler.SchedulerConfig) {
+	p.scheduler.SetConfig(config)
+}
+
+// StartReconciler launches the orphan-instance GC in the background. It blocks until
+// ctx is cancelled, so callers should run it in its own goroutine.
+func (p *VastProvider) StartReconciler(ctx context.Context) {
+	NewReconciler(p.client, p.kubeClient, p.nodeName).Run(ctx)
+}
+
+// StartDisruptionController launches the consolidation/drift/expiration loop
+// in the background. It blocks until ctx is cancelled, so callers should run
+// it in its own goroutine.
+func (p *VastProvider) StartDisruptionController(ctx context.Context) {
+	p.disruption.Run(ctx)
+}
+
+// StartStateReconciler launches the instance-state reconciler in the
+// background. It blocks until ctx is cancelled, so callers should run it in
+// its own goroutine.
+func (p *VastProvider) StartStateReconciler(ctx context.Context) {
+	p.stateRecon.Run(ctx)
+}
+
 // ConfigureNode enables a provider to configure the node object that will be used for the provider
 func (p *VastProvider) ConfigureNode(ctx context.Context, node *corev1.Node) {
 	log := pkglog.G(ctx).WithField("node", p.nodeName)
@@ -193,16 +261,52 @@ func (p *VastProvider) CreatePod(ctx context.Context, pod *corev1.Pod) error {
 		return fmt.Errorf("failed to launch Vast.ai instance: %w", err)
 	}
 
+	gpuType, region := p.scheduler.Config().GPUType, instance.Geolocation
+
+	// Pods that opt in block here until the instance is Running and its
+	// readinessProbe passes, instead of reporting Running as soon as the
+	// instance exists. Fast-start workloads skip this and rely on
+	// statusManager to catch up to Running asynchronously.
+	if pod.Annotations[WaitReadyAnnotation] == "true" {
+		readyStart := time.Now()
+		if err := p.waitUntilHealthy(ctx, pod, instance); err != nil {
+			metrics.ObservePodToReadyDuration("timeout", gpuType, region, readyStart)
+			log.WithError(err).Warn("instance never became healthy, tearing down")
+			if destroyErr := p.client.DestroyInstance(ctx, instance.ID); destroyErr != nil {
+				log.WithError(destroyErr).Warn("failed to destroy unhealthy instance")
+			}
+
+			p.mu.Lock()
+			p.failedPods[pod.Name] = failedPodStatus(pod, VastInstanceUnhealthyReason, err)
+			p.mu.Unlock()
+
+			return fmt.Errorf("instance %d never became healthy: %w", instance.ID, err)
+		}
+		metrics.ObservePodToReadyDuration("success", gpuType, region, readyStart)
+	}
+
 	// Store instance mapping
 	p.mu.Lock()
 	p.instances[pod.Name] = instance
 	p.mu.Unlock()
 
 	// Update service endpoints
+	endpointStart := time.Now()
 	if err := p.endpoints.UpdatePodEndpoints(ctx, pod, instance); err != nil {
+		metrics.ObserveEndpointPropagationDuration("error", gpuType, region, endpointStart)
 		log.WithError(err).Warn("Failed to update service endpoints")
+	} else {
+		metrics.ObserveEndpointPropagationDuration("success", gpuType, region, endpointStart)
 	}
 
+	// Start watching the instance's status so NotifyPods can report changes
+	// as they happen instead of waiting for the next poll.
+	p.statusMgr.Track(ctx, pod, instance)
+
+	// Hand the instance off to the disruption controller so it's considered
+	// for consolidation, drift, and expiration going forward.
+	p.disruption.RecordLaunch(pod, instance, p.scheduler.Config())
+
 	log.WithField("instanceId", instance.ID).Info("Pod created on Vast.ai")
 	return nil
 }
@@ -236,9 +340,16 @@ func (p *VastProvider) DeletePod(ctx context.Context, pod *corev1.Pod) error {
 
 	p.mu.RLock()
 	instance, exists := p.instances[pod.Name]
+	_, failed := p.failedPods[pod.Name]
 	p.mu.RUnlock()
 
 	if !exists {
+		if failed {
+			p.mu.Lock()
+			delete(p.failedPods, pod.Name)
+			p.mu.Unlock()
+			return nil
+		}
 		log.Warn("Instance for pod not found, assuming already deleted")
 		return nil
 	}
@@ -258,6 +369,9 @@ func (p *VastProvider) DeletePod(ctx context.Context, pod *corev1.Pod) error {
 		log.WithError(err).Warn("Failed to cleanup service endpoints")
 	}
 
+	p.statusMgr.Untrack(pod.Name)
+	p.disruption.Forget(pod.Name)
+
 	log.WithField("instanceId", instance.ID).Info("Pod deleted from Vast.ai")
 	return nil
 }
@@ -265,9 +379,20 @@ func (p *VastProvider) DeletePod(ctx context.Context, pod *corev1.Pod) error {
 // GetPod returns a pod by name that is being managed by the provider
 func (p *VastProvider) GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
 	p.mu.RLock()
+	failedStatus, failed := p.failedPods[name]
 	instance, exists := p.instances[name]
 	p.mu.RUnlock()
 
+	if failed {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Status: *failedStatus,
+		}, nil
+	}
+
 	if !exists {
 		return nil, errdefs.NotFound("pod not found")
 	}
@@ -285,17 +410,17 @@ func (p *VastProvider) GetPod(ctx context.Context, namespace, name string) (*cor
 			Name:      name,
 		},
 		Status: corev1.PodStatus{
-			Phase: p.convertInstanceStatusToPodPhase(status),
+			Phase: convertInstanceStatusToPodPhase(status),
 			Conditions: []corev1.PodCondition{
 				{
 					Type:   corev1.PodReady,
-					Status: p.convertInstanceStatusToConditionStatus(status),
+					Status: convertInstanceStatusToConditionStatus(status),
 					LastTransitionTime: metav1.Now(),
 				},
 			},
 			ContainerStatuses: []corev1.ContainerStatus{
 				{
-					Name:  "june-multi-gpu",
+					Name:  p.statusMgr.ContainerName(name),
 					Ready: status == vapi.InstanceStatusRunning,
 					State: corev1.ContainerState{
 						Running: &corev1.ContainerStateRunning{
@@ -322,10 +447,17 @@ func (p *VastProvider) GetPodStatus(ctx context.Context, namespace, name string)
 // GetPods returns a list of all pods known to be running within the provider
 func (p *VastProvider) GetPods(ctx context.Context) ([]*corev1.Pod, error) {
 	p.mu.RLock()
-	defer p.mu.RUnlock()
+	podNames := make([]string, 0, len(p.instances)+len(p.failedPods))
+	for podName := range p.instances {
+		podNames = append(podNames, podName)
+	}
+	for podName := range p.failedPods {
+		podNames = append(podNames, podName)
+	}
+	p.mu.RUnlock()
 
 	var pods []*corev1.Pod
-	for podName := range p.instances {
+	for _, podName := range podNames {
 		pod, err := p.GetPod(ctx, "default", podName)
 		if err != nil {
 			continue
@@ -336,9 +468,18 @@ func (p *VastProvider) GetPods(ctx context.Context) ([]*corev1.Pod, error) {
 	return pods, nil
 }
 
-// RunInContainer executes a command in a container in the pod
+// RunInContainer execs cmd inside the pod's instance over the pooled SSH
+// connection (see pkg/provider/vast/api/sshexec), blocking until it exits.
 func (p *VastProvider) RunInContainer(ctx context.Context, namespace, podName, containerName string, cmd []string, attach vkapi.AttachIO) error {
-	return fmt.Errorf("RunInContainer not supported for Vast.ai provider")
+	p.mu.RLock()
+	instance, exists := p.instances[podName]
+	p.mu.RUnlock()
+
+	if !exists {
+		return errdefs.NotFound("pod not found")
+	}
+
+	return p.client.RunInContainer(ctx, instance.ID, strings.Join(cmd, " "), attach)
 }
 
 // GetPodLogs retrieves the logs of a container of the specified pod
@@ -351,62 +492,74 @@ func (p *VastProvider) GetPodLogs(ctx context.Context, namespace, podName, conta
 		return nil, errdefs.NotFound("pod not found")
 	}
 
-	return p.client.GetInstanceLogs(ctx, instance.ID, vapi.ContainerLogOpts{})
+	return p.client.GetInstanceLogs(ctx, instance.ID, vapi.ContainerLogOpts{
+		Tail:         opts.Tail,
+		SinceSeconds: opts.SinceSeconds,
+		SinceTime:    opts.SinceTime,
+		Timestamps:   opts.Timestamps,
+		Follow:       opts.Follow,
+		Previous:     opts.Previous,
+		LimitBytes:   opts.LimitBytes,
+	})
 }
 
-// GetStatsSummary returns the stats for all pods known by this provider
+// GetStatsSummary returns the stats for all pods known by this provider,
+// collecting per-pod CPU/memory/GPU stats from each backing instance over
+// SSH (see pkg/provider/vast/stats) and summing them into the node totals.
+// A pod whose collection fails is skipped rather than failing the whole
+// summary, since one unreachable instance shouldn't blank out every other
+// pod's stats.
 func (p *VastProvider) GetStatsSummary(ctx context.Context) (*Summary, error) {
+	p.mu.RLock()
+	instances := make(map[string]*vapi.Instance, len(p.instances))
+	for podName, instance := range p.instances {
+		instances[podName] = instance
+	}
+	p.mu.RUnlock()
+
+	log := pkglog.G(ctx).WithField("provider", "vast.ai")
+
+	var podStats []stats.PodStats
+	var nodeCPU stats.CPUStats
+	var nodeMemory stats.MemoryStats
+
+	for podName, instance := range instances {
+		ps, err := p.statsCollector.Collect(ctx, podName, "default", p.statusMgr.ContainerName(podName), instance.CreatedAt, instance.ID)
+		if err != nil {
+			log.WithError(err).Warnf("failed to collect stats for pod %s, omitting from summary", podName)
+			continue
+		}
+		podStats = append(podStats, *ps)
+		for _, container := range ps.Containers {
+			nodeCPU.UsageCoreNanoSeconds += container.CPU.UsageCoreNanoSeconds
+			nodeMemory.UsageBytes += container.Memory.UsageBytes
+			nodeMemory.WorkingSetBytes += container.Memory.WorkingSetBytes
+		}
+	}
+
 	return &Summary{
 		Node: &NodeStats{
 			NodeName:  p.nodeName,
 			StartTime: time.Now().Format(time.RFC3339),
+			CPU:       nodeCPU,
+			Memory:    nodeMemory,
 		},
+		Pods: podStats,
 	}, nil
 }
 
-// NotifyPods instructs the notifier to call the passed in function when the pod status changes
+// NotifyPods instructs the notifier to call the passed in function when the pod status changes.
+// Updates are event-driven: statusManager subscribes to each instance's event stream and only
+// calls notifierFunc when the cached status actually differs, falling back to a periodic full
+// reconciliation pass as a safety net for any transition the event stream missed.
 func (p *VastProvider) NotifyPods(ctx context.Context, notifierFunc func(*corev1.Pod)) {
 	log := pkglog.G(ctx).WithField("provider", "vast.ai")
-	log.Info("Starting pod status monitoring")
-
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
+	log.Info("Starting event-driven pod status monitoring")
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			p.checkAndNotifyPodStatuses(ctx, notifierFunc)
-		}
-	}
+	p.statusMgr.Run(ctx, notifierFunc)
 }
 
-func (p *VastProvider) checkAndNotifyPodStatuses(ctx context.Context, notifierFunc func(*corev1.Pod)) {
-	p.mu.RLock()
-	instances := make(map[string]*vapi.Instance)
-	for k, v := range p.instances {
-		instances[k] = v
-	}
-	p.mu.RUnlock()
-
-	for podName, instance := range instances {
-		status, err := p.client.GetInstanceStatus(ctx, instance.ID)
-		if err != nil {
-			continue
-		}
-
-		pod, err := p.GetPod(ctx, "default", podName)
-		if err != nil {
-			continue
-		}
-
-		pod.Status.Phase = p.convertInstanceStatusToPodPhase(status)
-		notifierFunc(pod)
-	}
-}
-
-func (p *VastProvider) convertInstanceStatusToPodPhase(status vapi.InstanceStatus) corev1.PodPhase {
+func convertInstanceStatusToPodPhase(status vapi.InstanceStatus) corev1.PodPhase {
 	switch status {
 	case vapi.InstanceStatusRunning:
 		return corev1.PodRunning
@@ -421,9 +574,27 @@ func (p *VastProvider) convertInstanceStatusToPodPhase(status vapi.InstanceStatu
 	}
 }
 
-func (p *VastProvider) convertInstanceStatusToConditionStatus(status vapi.InstanceStatus) corev1.ConditionStatus {
+func convertInstanceStatusToConditionStatus(status vapi.InstanceStatus) corev1.ConditionStatus {
 	if status == vapi.InstanceStatusRunning {
 		return corev1.ConditionTrue
 	}
 	return corev1.ConditionFalse
-}
\ No newline at end of file
+}
+
+// defaultContainerName is the ContainerStatus/stats name reported when pod is
+// unavailable (e.g. GetPod for a pod this process never tracked a spec for).
+// Every code path that does have the pod should call managedContainerName
+// instead, so the name follows the pod's own spec rather than drifting from it.
+const defaultContainerName = "june-multi-gpu"
+
+// managedContainerName returns the single container name this provider
+// reports pod's instance status and stats under: pod's first container name,
+// since Vast.ai instances are single-container. Falls back to
+// defaultContainerName if pod is nil or has no containers, which shouldn't
+// happen for any pod the API server admitted.
+func managedContainerName(pod *corev1.Pod) string {
+	if pod != nil && len(pod.Spec.Containers) > 0 && pod.Spec.Containers[0].Name != "" {
+		return pod.Spec.Containers[0].Name
+	}
+	return defaultContainerName
+}
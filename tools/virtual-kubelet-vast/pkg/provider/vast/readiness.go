@@ -0,0 +1,203 @@
+package vast
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	vapi "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+const (
+	// WaitReadyAnnotation opts a pod into CreatePod blocking on
+	// waitUntilHealthy instead of returning as soon as the instance is
+	// launched. Fast-start workloads that don't set it get the old
+	// behavior: CreatePod returns once the instance exists, and status
+	// catches up to Running asynchronously via statusManager.
+	WaitReadyAnnotation = "vast.ozzu.io/wait-ready"
+
+	// DefaultReadyTimeout bounds how long CreatePod waits for an instance to
+	// report Running and pass its pod's readinessProbe before giving up,
+	// used unless overridden by SetReadyTimeout (wired from
+	// --vast-ready-timeout in cmd/vk-vast).
+	DefaultReadyTimeout = 6 * time.Minute
+
+	// VastInstanceUnhealthyReason is the pod condition/container status
+	// reason set when waitUntilHealthy times out.
+	VastInstanceUnhealthyReason = "VastInstanceUnhealthy"
+
+	readinessPollInterval = 5 * time.Second
+	probeTimeout          = 5 * time.Second
+)
+
+// SetReadyTimeout overrides how long CreatePod waits for wait-ready pods to
+// become healthy before giving up, destroying the instance, and failing the
+// pod.
+func (p *VastProvider) SetReadyTimeout(timeout time.Duration) {
+	p.readyTimeout = timeout
+}
+
+// waitUntilHealthy blocks until instance reports InstanceStatusRunning and
+// pod's first container's readinessProbe (if any) succeeds against the
+// instance's mapped endpoint, mirroring minikube's "block until the
+// apiserver services requests" startup gate. It returns the last probe
+// error once p.readyTimeout elapses.
+func (p *VastProvider) waitUntilHealthy(ctx context.Context, pod *corev1.Pod, instance *vapi.Instance) error {
+	timeout := p.readyTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadyTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		status, err := p.client.GetInstanceStatus(ctx, instance.ID)
+		switch {
+		case err != nil:
+			lastErr = err
+		case status == vapi.InstanceStatusFailed:
+			return fmt.Errorf("instance %d failed to start", instance.ID)
+		case status == vapi.InstanceStatusRunning:
+			probeErr := p.runReadinessProbe(ctx, pod, instance)
+			if probeErr == nil {
+				return nil
+			}
+			lastErr = probeErr
+		default:
+			lastErr = fmt.Errorf("instance %d not running yet (status: %s)", instance.ID, status)
+		}
+
+		if time.Now().After(deadline) {
+			return lastErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runReadinessProbe executes pod's first container's readinessProbe against
+// instance once, returning nil if it's unset (Running is then enough).
+func (p *VastProvider) runReadinessProbe(ctx context.Context, pod *corev1.Pod, instance *vapi.Instance) error {
+	if len(pod.Spec.Containers) == 0 || pod.Spec.Containers[0].ReadinessProbe == nil {
+		return nil
+	}
+
+	probe := pod.Spec.Containers[0].ReadinessProbe
+	switch {
+	case probe.HTTPGet != nil:
+		return p.probeHTTP(ctx, instance, probe.HTTPGet)
+	case probe.TCPSocket != nil:
+		return p.probeTCP(ctx, instance, probe.TCPSocket)
+	case probe.Exec != nil:
+		return p.client.RunExecProbe(ctx, instance.ID, probe.Exec.Command)
+	default:
+		return nil
+	}
+}
+
+func (p *VastProvider) probeHTTP(ctx context.Context, instance *vapi.Instance, action *corev1.HTTPGetAction) error {
+	port, err := mappedPort(instance, action.Port)
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if action.Scheme == corev1.URISchemeHTTPS {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s:%d%s", scheme, instance.PublicIP, port, action.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for _, h := range action.HTTPHeaders {
+		req.Header.Set(h.Name, h.Value)
+	}
+
+	client := &http.Client{Timeout: probeTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP readiness probe %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("HTTP readiness probe %s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *VastProvider) probeTCP(ctx context.Context, instance *vapi.Instance, action *corev1.TCPSocketAction) error {
+	port, err := mappedPort(instance, action.Port)
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", instance.PublicIP, port)
+	conn, err := (&net.Dialer{Timeout: probeTimeout}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("TCP readiness probe %s failed: %w", addr, err)
+	}
+	conn.Close()
+	return nil
+}
+
+// mappedPort resolves a probe's container port to the external port
+// Vast.ai mapped it to. Named ports aren't supported since Instance.Ports
+// only tracks the numeric container->host mapping.
+func mappedPort(instance *vapi.Instance, port intstr.IntOrString) (int, error) {
+	if port.Type != intstr.Int {
+		return 0, fmt.Errorf("named container ports are not supported for readiness probes, got %q", port.StrVal)
+	}
+	mapped, ok := instance.Ports[int(port.IntVal)]
+	if !ok {
+		return 0, fmt.Errorf("container port %d not found in instance %d's port mapping", port.IntVal, instance.ID)
+	}
+	return mapped, nil
+}
+
+// failedPodStatus builds the PodStatus GetPod/GetPodStatus report for pod
+// whose instance is gone or never became healthy, for some reason (e.g.
+// VastInstanceUnhealthyReason, VastInstanceLostReason).
+func failedPodStatus(pod *corev1.Pod, reason string, cause error) *corev1.PodStatus {
+	now := metav1.Now()
+	return &corev1.PodStatus{
+		Phase: corev1.PodFailed,
+		Conditions: []corev1.PodCondition{
+			{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: now,
+				Reason:             reason,
+				Message:            cause.Error(),
+			},
+		},
+		ContainerStatuses: []corev1.ContainerStatus{
+			{
+				Name:  managedContainerName(pod),
+				Ready: false,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						Reason:     reason,
+						Message:    cause.Error(),
+						FinishedAt: now,
+					},
+				},
+			},
+		},
+	}
+}
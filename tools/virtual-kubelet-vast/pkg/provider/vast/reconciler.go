@@ -0,0 +1,181 @@
+package vast
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	vapi "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+const (
+	vkClientID = "virtual-kubelet-june"
+
+	// DefaultReconcileInterval is how often the GC sweeps for orphaned instances.
+	DefaultReconcileInterval = 5 * time.Minute
+
+	// terminalPodGracePeriod is how long a Succeeded/Failed pod is left alone before
+	// its instance is reclaimed, to give the status pipeline time to settle.
+	terminalPodGracePeriod = 10 * time.Minute
+
+	// failedInstanceGracePeriod is how long an instance may sit in
+	// InstanceStatusFailed before the GC reclaims it.
+	failedInstanceGracePeriod = 15 * time.Minute
+)
+
+// Reconciler reconciles Vast.ai instances against the pods that are supposed to own
+// them, destroying instances that have become orphaned. It mirrors the pattern used
+// by the Kubernetes garbage collector: list both sides, diff, and act on the
+// difference rather than trusting any single in-memory cache.
+type Reconciler struct {
+	client     *vapi.VastClient
+	kubeClient kubernetes.Interface
+	nodeName   string
+	interval   time.Duration
+	dryRun     bool
+
+	// failedSince tracks how long each instance has been observed in
+	// InstanceStatusFailed, since Vast.ai does not report a transition timestamp.
+	failedSince map[int]time.Time
+}
+
+// NewReconciler creates a GC reconciler for the given node. Dry-run mode is enabled
+// by setting VAST_GC_DRYRUN=1, in which case reclamation decisions are logged but no
+// DestroyInstance calls are made.
+func NewReconciler(client *vapi.VastClient, kubeClient kubernetes.Interface, nodeName string) *Reconciler {
+	return &Reconciler{
+		client:      client,
+		kubeClient:  kubeClient,
+		nodeName:    nodeName,
+		interval:    DefaultReconcileInterval,
+		dryRun:      os.Getenv("VAST_GC_DRYRUN") == "1",
+		failedSince: make(map[int]time.Time),
+	}
+}
+
+// Run starts the reconciliation loop and blocks until ctx is cancelled.
+func (r *Reconciler) Run(ctx context.Context) {
+	log := klog.FromContext(ctx).WithValues("component", "vast-gc")
+	if r.dryRun {
+		log.Info("Starting orphan-instance GC in dry-run mode (VAST_GC_DRYRUN=1)")
+	} else {
+		log.Info("Starting orphan-instance GC")
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.reconcileOnce(ctx); err != nil {
+				log.Error(err, "GC reconciliation pass failed")
+			}
+		}
+	}
+}
+
+func (r *Reconciler) reconcileOnce(ctx context.Context) error {
+	log := klog.FromContext(ctx).WithValues("component", "vast-gc")
+
+	instances, err := r.client.ListInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list vast instances: %w", err)
+	}
+
+	pods, err := r.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + r.nodeName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pods on node %s: %w", r.nodeName, err)
+	}
+
+	podsByUID := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		podsByUID[string(pod.UID)] = pod
+	}
+
+	now := time.Now()
+	seenFailed := make(map[int]bool)
+
+	for _, instance := range instances {
+		if instance.ClientID != "" && instance.ClientID != vkClientID {
+			continue
+		}
+
+		label, ok := vapi.ParseInstanceLabel(instance.Label)
+		if !ok || label.Node != r.nodeName {
+			continue
+		}
+
+		pod, podExists := podsByUID[label.UID]
+
+		switch {
+		case !podExists:
+			r.reclaim(ctx, log, instance, "pod no longer exists for instance label %q", instance.Label)
+
+		case isTerminal(pod) && now.Sub(podTerminalSince(pod)) > terminalPodGracePeriod:
+			r.reclaim(ctx, log, instance, "pod %s/%s has been %s for over %s", pod.Namespace, pod.Name, pod.Status.Phase, terminalPodGracePeriod)
+
+		case instance.Status == vapi.InstanceStatusFailed:
+			seenFailed[instance.ID] = true
+			since, tracked := r.failedSince[instance.ID]
+			if !tracked {
+				r.failedSince[instance.ID] = now
+				break
+			}
+			if now.Sub(since) > failedInstanceGracePeriod {
+				r.reclaim(ctx, log, instance, "instance %d has been failed for over %s", instance.ID, failedInstanceGracePeriod)
+			}
+		}
+	}
+
+	// Drop failure-tracking entries for instances that recovered or disappeared.
+	for id := range r.failedSince {
+		if !seenFailed[id] {
+			delete(r.failedSince, id)
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) reclaim(ctx context.Context, log klog.Logger, instance vapi.Instance, reasonFmt string, args ...interface{}) {
+	reason := fmt.Sprintf(reasonFmt, args...)
+
+	if r.dryRun {
+		log.Info("would reclaim orphaned instance (dry-run)", "instanceId", instance.ID, "reason", reason)
+		return
+	}
+
+	log.Info("reclaiming orphaned instance", "instanceId", instance.ID, "reason", reason)
+	if err := r.client.DestroyInstance(ctx, instance.ID); err != nil {
+		log.Error(err, "failed to destroy orphaned instance", "instanceId", instance.ID)
+		return
+	}
+	delete(r.failedSince, instance.ID)
+}
+
+func isTerminal(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// podTerminalSince returns the best available timestamp for when the pod entered its
+// terminal phase, falling back to the pod's creation time if no better signal exists.
+func podTerminalSince(pod *corev1.Pod) time.Time {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+const defaultExtenderTimeout = 2 * time.Second
+
+// extenderRequest is the body POSTed to an extender URL.
+type extenderRequest struct {
+	Offer api.InstanceOffer `json:"offer"`
+	Pod   *corev1.Pod       `json:"pod"`
+}
+
+// extenderResponse is the body an extender is expected to reply with. Score
+// must be in [0, 1], the same contract as a built-in Priority.
+type extenderResponse struct {
+	Score float64  `json:"score"`
+	Notes []string `json:"notes,omitempty"`
+}
+
+// callExtender POSTs offer and pod to the extender's URL and returns its
+// score, scaled by the extender's configured Weight.
+func callExtender(ctx context.Context, httpClient *http.Client, extender ExtenderConfig, offer api.InstanceOffer, pod *corev1.Pod) (float64, []string, error) {
+	timeout := defaultExtenderTimeout
+	if extender.TimeoutMS > 0 {
+		timeout = time.Duration(extender.TimeoutMS) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(extenderRequest{Offer: offer, Pod: pod})
+	if err != nil {
+		return 0, nil, fmt.Errorf("extender %s: failed to marshal request: %w", extender.Name, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, extender.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("extender %s: failed to build request: %w", extender.Name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("extender %s: request failed: %w", extender.Name, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("extender %s: failed to read response: %w", extender.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, nil, fmt.Errorf("extender %s: unexpected status %d: %s", extender.Name, resp.StatusCode, respBody)
+	}
+
+	var parsed extenderResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return 0, nil, fmt.Errorf("extender %s: failed to parse response: %w", extender.Name, err)
+	}
+
+	return parsed.Score * extender.Weight, parsed.Notes, nil
+}
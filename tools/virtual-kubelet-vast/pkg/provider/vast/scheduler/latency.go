@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/latency"
+)
+
+// sshProbePort is the port latency measurements dial, since every Vast.ai
+// offer exposes SSH and it's reachable before any other port mapping exists.
+const sshProbePort = 22
+
+type latencyProviderContextKey struct{}
+
+func withLatencyProvider(ctx context.Context, provider latency.LatencyProvider) context.Context {
+	return context.WithValue(ctx, latencyProviderContextKey{}, provider)
+}
+
+func latencyProviderFromContext(ctx context.Context) latency.LatencyProvider {
+	if provider, ok := ctx.Value(latencyProviderContextKey{}).(latency.LatencyProvider); ok {
+		return provider
+	}
+	return unavailableLatencyProvider{}
+}
+
+// unavailableLatencyProvider is the latencyProviderFromContext fallback when
+// no scheduler wired a LatencyProvider into the context. Every measurement
+// fails, so predicates/priorities take their "probe failed" path instead of
+// dereferencing a nil provider.
+type unavailableLatencyProvider struct{}
+
+func (unavailableLatencyProvider) Measure(context.Context, string, latency.Target) (time.Duration, error) {
+	return 0, fmt.Errorf("no latency provider configured")
+}
+
+// latencyHostID is the cache key a measurement is stored under. Vast's search
+// API doesn't separately expose a stable physical-host identifier, so the
+// offer ID is used as an approximation.
+func latencyHostID(offer api.InstanceOffer) string {
+	return fmt.Sprintf("%d", offer.ID)
+}
+
+// latencyTarget is where an offer's latency is measured: its SSH port, the
+// one mapping guaranteed to exist before an instance is even launched.
+func latencyTarget(offer api.InstanceOffer) latency.Target {
+	return latency.Target{Host: offer.PublicIPAddr, Port: sshProbePort}
+}
@@ -0,0 +1,26 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Per-phase response-time histograms (search, scoring, create-instance,
+// endpoint propagation, pod-to-ready) live in the metrics package, which both
+// this package and the provider/disruption packages import; see
+// metrics.ObserveSearchDuration et al.
+
+var (
+	schedulingAttemptsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vast_scheduler_attempts_total",
+		Help: "Total number of SelectAndLaunchInstance attempts, labeled by outcome (scheduled, preempted, failed).",
+	}, []string{"outcome"})
+
+	preemptionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "vast_scheduler_preemptions_total",
+		Help: "Total number of instances evicted by PreemptForPod to make room for a higher-priority pod.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(schedulingAttemptsTotal, preemptionsTotal)
+}
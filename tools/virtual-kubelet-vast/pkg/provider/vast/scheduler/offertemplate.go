@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	vastv1alpha1 "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/apis/vast/v1alpha1"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+// OfferTemplateProvider resolves the VastOfferTemplate a pod names via the
+// vast.ozzu.io/offer-template annotation and records the outcome of matching
+// against it. It's expressed as an interface, rather than importing a
+// concrete clientset, so the scheduler package doesn't need a live apiserver
+// or rest.Config to be unit-testable.
+type OfferTemplateProvider interface {
+	Get(ctx context.Context, namespace, name string) (*vastv1alpha1.VastOfferTemplateSpec, error)
+	RecordMatch(ctx context.Context, namespace, name string, pod *corev1.Pod, offer api.InstanceOffer) error
+}
+
+// SetOfferTemplateProvider wires the resolver FindBestOffer uses to apply a
+// pod's referenced VastOfferTemplate. Templates are ignored - pods schedule
+// on SchedulerConfig alone - until this is called.
+func (s *InstanceScheduler) SetOfferTemplateProvider(provider OfferTemplateProvider) {
+	s.offerTemplates = provider
+}
+
+// applyOfferTemplate overrides criteria with any non-zero field the pod's
+// referenced VastOfferTemplate sets, and returns a GPU-model glob (if any) to
+// post-filter search results with, since Vast.ai's search API has no glob
+// support over gpu_name.
+func (s *InstanceScheduler) applyOfferTemplate(ctx context.Context, pod *corev1.Pod, criteria *api.SearchCriteria) (gpuGlob string) {
+	name := pod.Annotations[vastv1alpha1.OfferTemplateAnnotation]
+	if name == "" || s.offerTemplates == nil {
+		return ""
+	}
+
+	log := klog.FromContext(ctx)
+	spec, err := s.offerTemplates.Get(ctx, pod.Namespace, name)
+	if err != nil {
+		log.Info("failed to resolve offer template, falling back to scheduler config", "template", name, "error", err)
+		return ""
+	}
+
+	if spec.MinVRAMGB > 0 {
+		criteria.MinGPUMemoryGB = spec.MinVRAMGB
+	}
+	if spec.MaxPricePerHour > 0 {
+		criteria.MaxPricePerHour = spec.MaxPricePerHour
+	}
+	if spec.MinDLPerf > 0 {
+		criteria.MinDLPerf = spec.MinDLPerf
+	}
+	if spec.MinReliability > 0 {
+		criteria.MinReliability = spec.MinReliability
+	}
+	if spec.MinDiskGB > 0 {
+		criteria.MinDiskGB = spec.MinDiskGB
+	}
+	if len(spec.Regions) > 0 {
+		criteria.PreferredRegions = spec.Regions
+	}
+
+	return spec.GPUModelGlob
+}
+
+// filterByGPUModelGlob drops offers whose GPUName doesn't match glob. An
+// empty or invalid glob is treated as "no constraint".
+func filterByGPUModelGlob(offers []api.InstanceOffer, glob string) []api.InstanceOffer {
+	if glob == "" {
+		return offers
+	}
+	if _, err := path.Match(glob, ""); err != nil {
+		return offers
+	}
+
+	filtered := make([]api.InstanceOffer, 0, len(offers))
+	for _, offer := range offers {
+		if matched, err := path.Match(glob, offer.GPUName); err == nil && matched {
+			filtered = append(filtered, offer)
+		}
+	}
+	return filtered
+}
+
+// recordOfferTemplateMatch tells the pod's referenced VastOfferTemplate (if
+// any) about the offer it was just launched on, for operators to inspect via
+// `kubectl get vastoffertemplate -o yaml`.
+func (s *InstanceScheduler) recordOfferTemplateMatch(ctx context.Context, pod *corev1.Pod, offer api.InstanceOffer) {
+	name := pod.Annotations[vastv1alpha1.OfferTemplateAnnotation]
+	if name == "" || s.offerTemplates == nil {
+		return
+	}
+
+	if err := s.offerTemplates.RecordMatch(ctx, pod.Namespace, name, pod, offer); err != nil {
+		klog.FromContext(ctx).Info("failed to record offer template match", "template", name, "error", err)
+	}
+}
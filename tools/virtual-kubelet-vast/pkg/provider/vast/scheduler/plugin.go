@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+// Predicate is a hard filter applied before scoring, mirroring a kube-scheduler
+// predicate: an offer that fails any enabled Predicate is dropped from
+// consideration entirely, regardless of how well it would otherwise score.
+type Predicate interface {
+	Name() string
+	Filter(ctx context.Context, offer api.InstanceOffer, pod *corev1.Pod) (bool, string, error)
+}
+
+// Priority is a soft scorer applied to offers that survive the predicate phase,
+// mirroring a kube-scheduler priority function. Score must return a value in
+// [0, 1]; the framework multiplies it by Weight() before summing across plugins.
+type Priority interface {
+	Name() string
+	Score(ctx context.Context, offer api.InstanceOffer, pod *corev1.Pod) (float64, []string, error)
+	Weight() float64
+}
+
+// PluginRegistry holds the built-in predicates and priorities registered via
+// init(), keyed by name so a SchedulingProfile can select a subset by name.
+type PluginRegistry struct {
+	mu         sync.RWMutex
+	predicates map[string]Predicate
+	priorities map[string]Priority
+}
+
+var defaultRegistry = &PluginRegistry{
+	predicates: make(map[string]Predicate),
+	priorities: make(map[string]Priority),
+}
+
+// DefaultRegistry returns the process-wide registry that built-in plugins
+// register themselves into via init().
+func DefaultRegistry() *PluginRegistry {
+	return defaultRegistry
+}
+
+// RegisterPredicate adds a predicate to the default registry. It panics on a
+// duplicate name, the same way http.Handle panics on a duplicate pattern -
+// a name collision between built-ins is a programming error, not a runtime one.
+func RegisterPredicate(p Predicate) {
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+
+	if _, exists := defaultRegistry.predicates[p.Name()]; exists {
+		panic(fmt.Sprintf("scheduler: predicate %q already registered", p.Name()))
+	}
+	defaultRegistry.predicates[p.Name()] = p
+}
+
+// RegisterPriority adds a priority to the default registry. It panics on a
+// duplicate name; see RegisterPredicate.
+func RegisterPriority(p Priority) {
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+
+	if _, exists := defaultRegistry.priorities[p.Name()]; exists {
+		panic(fmt.Sprintf("scheduler: priority %q already registered", p.Name()))
+	}
+	defaultRegistry.priorities[p.Name()] = p
+}
+
+// Predicates returns the named predicates, in the order requested. An unknown
+// name is an error so a typo in a SchedulingProfile fails loudly at startup.
+func (r *PluginRegistry) Predicates(names []string) ([]Predicate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Predicate, 0, len(names))
+	for _, name := range names {
+		p, ok := r.predicates[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown predicate %q", name)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Priorities returns the named priorities, in the order requested, wrapped so
+// that Weight() reflects any override from weights. An unknown name is an
+// error so a typo in a SchedulingProfile fails loudly at startup.
+func (r *PluginRegistry) Priorities(names []string, weights map[string]float64) ([]Priority, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Priority, 0, len(names))
+	for _, name := range names {
+		p, ok := r.priorities[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown priority %q", name)
+		}
+		if w, override := weights[name]; override {
+			p = weightedPriority{Priority: p, weight: w}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// weightedPriority overrides a wrapped Priority's Weight() with a profile-supplied
+// value while delegating Name() and Score() unchanged.
+type weightedPriority struct {
+	Priority
+	weight float64
+}
+
+func (w weightedPriority) Weight() float64 {
+	return w.weight
+}
+
+type configContextKey struct{}
+
+// withConfig attaches the SchedulerConfig for the current scheduling run to ctx.
+// Plugins read it back with configFromContext rather than taking it as a
+// parameter, keeping the Predicate/Priority signatures stable as new config
+// fields are added.
+func withConfig(ctx context.Context, config *SchedulerConfig) context.Context {
+	return context.WithValue(ctx, configContextKey{}, config)
+}
+
+// configFromContext returns the SchedulerConfig attached by withConfig, or a
+// zero-value config if none was attached (which predicates/priorities should
+// treat as "no constraint configured" rather than panicking).
+func configFromContext(ctx context.Context) *SchedulerConfig {
+	if config, ok := ctx.Value(configContextKey{}).(*SchedulerConfig); ok && config != nil {
+		return config
+	}
+	return &SchedulerConfig{}
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+func init() {
+	RegisterPredicate(gpuMatchPredicate{})
+	RegisterPredicate(priceCeilingPredicate{})
+	RegisterPredicate(minReliabilityPredicate{})
+	RegisterPredicate(regionAllowlistPredicate{})
+	RegisterPredicate(latencyCeilingPredicate{})
+}
+
+// gpuMatchPredicate rejects offers that can't actually fit the requested GPU
+// memory. SearchCriteria.MinGPUMemoryGB already asks Vast.ai's search API to
+// filter these out; this is a defense-in-depth check against stale or
+// inconsistent search results.
+type gpuMatchPredicate struct{}
+
+func (gpuMatchPredicate) Name() string { return "gpu-match" }
+
+func (gpuMatchPredicate) Filter(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (bool, string, error) {
+	config := configFromContext(ctx)
+	if config.MinGPUMemoryGB > 0 && offer.GPURam < config.MinGPUMemoryGB {
+		return false, fmt.Sprintf("gpu_ram %dGB below required %dGB", offer.GPURam, config.MinGPUMemoryGB), nil
+	}
+	return true, "", nil
+}
+
+// priceCeilingPredicate rejects offers priced above SchedulerConfig.MaxPricePerHour.
+type priceCeilingPredicate struct{}
+
+func (priceCeilingPredicate) Name() string { return "price-ceiling" }
+
+func (priceCeilingPredicate) Filter(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (bool, string, error) {
+	config := configFromContext(ctx)
+	if config.MaxPricePerHour > 0 && offer.DPH > config.MaxPricePerHour {
+		return false, fmt.Sprintf("$%.3f/hr above ceiling $%.3f/hr", offer.DPH, config.MaxPricePerHour), nil
+	}
+	return true, "", nil
+}
+
+// minReliabilityPredicate rejects offers below SchedulerConfig.MinReliability.
+type minReliabilityPredicate struct{}
+
+func (minReliabilityPredicate) Name() string { return "min-reliability" }
+
+func (minReliabilityPredicate) Filter(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (bool, string, error) {
+	config := configFromContext(ctx)
+	if offer.Reliability < config.MinReliability {
+		return false, fmt.Sprintf("reliability %.3f below minimum %.3f", offer.Reliability, config.MinReliability), nil
+	}
+	return true, "", nil
+}
+
+// regionAllowlistPredicate rejects offers whose geolocation matches
+// SchedulerConfig.BlockedRegions. Unlike gpu-match, price-ceiling, and
+// min-reliability, this has no server-side equivalent - SearchCriteria has no
+// blocklist field - so it is the only enforcement point for BlockedRegions
+// today.
+type regionAllowlistPredicate struct{}
+
+func (regionAllowlistPredicate) Name() string { return "region-allowlist" }
+
+func (regionAllowlistPredicate) Filter(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (bool, string, error) {
+	config := configFromContext(ctx)
+	location := strings.ToUpper(offer.Geolocation)
+	for _, blocked := range config.BlockedRegions {
+		if strings.HasPrefix(location, strings.ToUpper(blocked)) {
+			return false, fmt.Sprintf("geolocation %s matches blocked region %s", offer.Geolocation, blocked), nil
+		}
+	}
+	return true, "", nil
+}
+
+// latencyCeilingPredicate rejects offers whose measured round-trip latency
+// exceeds SchedulerConfig.MaxLatencyMS, so the priority phase only ranks
+// candidates that are actually reachable within budget. It's a no-op unless
+// LatencyCheckEnabled is set, since probing every offer isn't free.
+type latencyCeilingPredicate struct{}
+
+func (latencyCeilingPredicate) Name() string { return "latency-ceiling" }
+
+func (latencyCeilingPredicate) Filter(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (bool, string, error) {
+	config := configFromContext(ctx)
+	if !config.LatencyCheckEnabled || config.MaxLatencyMS <= 0 {
+		return true, "", nil
+	}
+
+	rtt, err := latencyProviderFromContext(ctx).Measure(ctx, latencyHostID(offer), latencyTarget(offer))
+	if err != nil {
+		return true, fmt.Sprintf("latency probe failed, not filtering: %v", err), nil
+	}
+
+	if rtt > time.Duration(config.MaxLatencyMS)*time.Millisecond {
+		return false, fmt.Sprintf("latency %s exceeds MaxLatencyMS %dms", rtt, config.MaxLatencyMS), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,269 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+// vastClientID must match vast.vkClientID. It's duplicated here rather than
+// imported because the vast package imports scheduler, not the other way
+// around - importing it back would create a cycle.
+const vastClientID = "virtual-kubelet-june"
+
+// NominatedInstanceAnnotation records the Vast instance ID a preemption freed
+// up for this pod, so a subsequent scheduling pass (or another node's
+// scheduler) knows the slot is already reserved rather than preempting again.
+const NominatedInstanceAnnotation = "vast.ai/nominated-instance-id"
+
+// PreemptionPolicy controls whether PreemptForPod is allowed to evict other
+// pods' instances to make room for a pod that didn't otherwise fit.
+type PreemptionPolicy string
+
+const (
+	// PreemptionNever disables preemption entirely; SelectAndLaunchInstance
+	// fails outright when no offer fits.
+	PreemptionNever PreemptionPolicy = "never"
+	// PreemptionLowerPriorityOnly only evicts instances backing pods with a
+	// strictly lower pod.Spec.Priority than the incoming pod.
+	PreemptionLowerPriorityOnly PreemptionPolicy = "lower-priority-only"
+	// PreemptionAnyBelowThreshold evicts any instance backing a pod whose
+	// priority is below SchedulerConfig.PreemptionPriorityThreshold,
+	// regardless of the incoming pod's own priority.
+	PreemptionAnyBelowThreshold PreemptionPolicy = "any-below-threshold"
+)
+
+// EndpointDrainer is the subset of vast.EndpointManager PreemptForPod needs to
+// cut traffic to a preempted pod immediately. It's expressed as an interface,
+// rather than importing the vast package directly, to avoid an import cycle
+// (vast.VastProvider wires this scheduler).
+type EndpointDrainer interface {
+	CleanupPodEndpoints(ctx context.Context, pod *corev1.Pod) error
+}
+
+// preemptible pairs a running pod with the Vast instance backing it, the unit
+// PreemptForPod reasons about when choosing eviction candidates.
+type preemptible struct {
+	pod      *corev1.Pod
+	instance api.Instance
+}
+
+// PreemptForPod tries to free enough room for pod by evicting lower-priority
+// instances, following the kube-scheduler Preempt pattern: gather candidates,
+// evict the minimum-disruption subset, and retry the launch after each
+// eviction rather than evicting everything up front. It's invoked by
+// SelectAndLaunchInstance once a normal placement attempt has failed.
+func (s *InstanceScheduler) PreemptForPod(ctx context.Context, pod *corev1.Pod) (*api.Instance, error) {
+	log := klog.FromContext(ctx)
+
+	policy := s.preemptionPolicy(pod.Namespace)
+	if policy == PreemptionNever || policy == "" {
+		return nil, fmt.Errorf("preemption disabled for namespace %s", pod.Namespace)
+	}
+	if s.kubeClient == nil {
+		return nil, fmt.Errorf("preemption not enabled on this scheduler")
+	}
+
+	candidates, err := s.preemptionCandidates(ctx, pod, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list preemption candidates: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no lower-priority instances available to preempt")
+	}
+
+	// Fewest evictions for the least disruption: sort ascending by victim
+	// priority so the lowest-priority (least valuable) instances go first.
+	sort.Slice(candidates, func(i, j int) bool {
+		return podPriority(candidates[i].pod) < podPriority(candidates[j].pod)
+	})
+
+	for _, victim := range candidates {
+		s.evict(ctx, log, victim, pod)
+
+		instance, err := s.findAndLaunch(ctx, pod)
+		if err == nil {
+			s.nominate(ctx, log, pod, instance)
+			return instance, nil
+		}
+		log.Info("preemption retry still doesn't fit, evicting next candidate", "pod", pod.Name, "reason", err)
+	}
+
+	return nil, fmt.Errorf("exhausted %d preemption candidates without finding room", len(candidates))
+}
+
+// preemptionPolicy returns the effective policy for namespace, falling back
+// to SchedulerConfig.PreemptionPolicy when no per-namespace override exists.
+func (s *InstanceScheduler) preemptionPolicy(namespace string) PreemptionPolicy {
+	if policy, ok := s.config.NamespacePreemptionPolicies[namespace]; ok {
+		return policy
+	}
+	return s.config.PreemptionPolicy
+}
+
+// preemptionCandidates lists running instances eligible for eviction under
+// policy, excluding the incoming pod itself.
+func (s *InstanceScheduler) preemptionCandidates(ctx context.Context, pod *corev1.Pod, policy PreemptionPolicy) ([]preemptible, error) {
+	running, err := s.runningInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	incomingPriority := podPriority(pod)
+
+	var candidates []preemptible
+	for _, r := range running {
+		if r.pod.UID == pod.UID {
+			continue
+		}
+
+		switch policy {
+		case PreemptionLowerPriorityOnly:
+			if podPriority(r.pod) < incomingPriority {
+				candidates = append(candidates, r)
+			}
+		case PreemptionAnyBelowThreshold:
+			if podPriority(r.pod) < s.config.PreemptionPriorityThreshold {
+				candidates = append(candidates, r)
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// runningInstances joins this scheduler's Vast instances to the pods
+// currently scheduled on nodeName, mirroring the list-both-sides-and-join
+// pattern vast.Reconciler uses for orphan GC.
+func (s *InstanceScheduler) runningInstances(ctx context.Context) ([]preemptible, error) {
+	if s.kubeClient == nil {
+		return nil, fmt.Errorf("preemption not enabled on this scheduler")
+	}
+
+	instances, err := s.client.ListInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vast instances: %w", err)
+	}
+
+	pods, err := s.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + s.nodeName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", s.nodeName, err)
+	}
+
+	podPtrs := make([]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		podPtrs[i] = &pods.Items[i]
+	}
+
+	return joinInstancesToPods(instances, podPtrs), nil
+}
+
+// joinInstancesToPods matches running Vast instances to the pods they back by
+// parsing each instance's label and looking up its UID, mirroring the
+// list-both-sides-and-join pattern vast.Reconciler uses for orphan GC.
+// Instances with an unparseable label or no surviving pod are dropped.
+func joinInstancesToPods(instances []api.Instance, pods []*corev1.Pod) []preemptible {
+	podsByUID := make(map[string]*corev1.Pod, len(pods))
+	for _, p := range pods {
+		podsByUID[string(p.UID)] = p
+	}
+
+	var running []preemptible
+	for _, instance := range instances {
+		if instance.ClientID != "" && instance.ClientID != vastClientID {
+			continue
+		}
+		label, ok := api.ParseInstanceLabel(instance.Label)
+		if !ok {
+			continue
+		}
+		pod, ok := podsByUID[label.UID]
+		if !ok {
+			continue
+		}
+		running = append(running, preemptible{pod: pod, instance: instance})
+	}
+	return running
+}
+
+// podPriority returns the pod's resolved scheduling priority, defaulting to 0
+// for pods with no PriorityClass (the API server always resolves
+// PriorityClassName into Spec.Priority, so that's the only field worth
+// reading here).
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+// evict gracefully terminates victim's Vast instance and drains its
+// endpoints so traffic stops before the pod's own delete handling catches up.
+func (s *InstanceScheduler) evict(ctx context.Context, log klog.Logger, victim preemptible, nominating *corev1.Pod) {
+	log.Info("preempting instance to make room for higher-priority pod", "victimPod", victim.pod.Name, "instanceId", victim.instance.ID, "nominatingPod", nominating.Name)
+
+	if s.endpoints != nil {
+		if err := s.endpoints.CleanupPodEndpoints(ctx, victim.pod); err != nil {
+			log.Error(err, "failed to drain endpoints for preempted pod", "pod", victim.pod.Name)
+		}
+	}
+
+	if err := s.client.DestroyInstance(ctx, victim.instance.ID); err != nil {
+		log.Error(err, "failed to destroy preempted instance", "instanceId", victim.instance.ID)
+	}
+	preemptionsTotal.Inc()
+
+	s.recordPreemptionEvent(victim.pod, nominating)
+}
+
+// recordPreemptionEvent emits a Preempted event on the victim pod naming the
+// pod it was evicted for, matching the disruption controller's pattern of
+// surfacing instance-replacement decisions as Kubernetes events.
+func (s *InstanceScheduler) recordPreemptionEvent(victim, nominating *corev1.Pod) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Eventf(&corev1.ObjectReference{
+		Kind:      "Pod",
+		Namespace: victim.Namespace,
+		Name:      victim.Name,
+		UID:       victim.UID,
+	}, corev1.EventTypeNormal, "Preempted", "Preempted to make room for higher-priority pod %s/%s", nominating.Namespace, nominating.Name)
+}
+
+// nominate records the instance PreemptForPod freed up for pod, so a
+// subsequent scheduling pass knows this slot is already reserved.
+func (s *InstanceScheduler) nominate(ctx context.Context, log klog.Logger, pod *corev1.Pod, instance *api.Instance) {
+	current, err := s.kubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		log.Error(err, "failed to fetch pod to annotate nominated instance", "pod", pod.Name)
+		return
+	}
+
+	if current.Annotations == nil {
+		current.Annotations = make(map[string]string)
+	}
+	current.Annotations[NominatedInstanceAnnotation] = strconv.Itoa(instance.ID)
+
+	if _, err := s.kubeClient.CoreV1().Pods(pod.Namespace).Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+		log.Error(err, "failed to annotate pod with nominated instance", "pod", pod.Name)
+	}
+}
+
+func newSchedulerEventRecorder(kubeClient kubernetes.Interface, nodeName string) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "vast-scheduler", Host: nodeName})
+}
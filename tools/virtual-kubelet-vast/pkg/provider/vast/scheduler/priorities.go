@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+func init() {
+	RegisterPriority(latencyPriority{})
+	RegisterPriority(pricePriority{})
+	RegisterPriority(geographicZoneBonusPriority{})
+	RegisterPriority(verifiedHostPriority{})
+	RegisterPriority(bandwidthPriority{})
+}
+
+// defaultLatencyCeilingMS is the decay scale used to normalize a latency
+// measurement into a score when SchedulerConfig.MaxLatencyMS isn't set -
+// latencyCeilingPredicate already filters obviously bad offers, so this only
+// needs to rank the survivors sensibly.
+const defaultLatencyCeilingMS = 200
+
+// latencyPriority scores offers by measured round-trip latency, using
+// exponential decay so small differences near zero latency matter more than
+// the same difference far from it.
+type latencyPriority struct{}
+
+func (latencyPriority) Name() string { return "latency" }
+func (latencyPriority) Weight() float64 { return 0.35 }
+
+func (latencyPriority) Score(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (float64, []string, error) {
+	config := configFromContext(ctx)
+	if !config.LatencyCheckEnabled {
+		return 0.5, []string{"latency check disabled"}, nil
+	}
+
+	rtt, err := latencyProviderFromContext(ctx).Measure(ctx, latencyHostID(offer), latencyTarget(offer))
+	if err != nil {
+		return 0.5, []string{fmt.Sprintf("latency probe failed, neutral score: %v", err)}, nil
+	}
+
+	ceiling := config.MaxLatencyMS
+	if ceiling <= 0 {
+		ceiling = defaultLatencyCeilingMS
+	}
+	score := math.Exp(-float64(rtt.Milliseconds()) / float64(ceiling))
+	return score, []string{fmt.Sprintf("latency: %s (score %.3f)", rtt, score)}, nil
+}
+
+// pricePriority favors cheaper offers, scoring 1.0 at $0/hr down to 0.0 at the
+// configured ceiling.
+type pricePriority struct{}
+
+func (pricePriority) Name() string { return "price" }
+func (pricePriority) Weight() float64 { return 0.25 }
+
+func (pricePriority) Score(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (float64, []string, error) {
+	config := configFromContext(ctx)
+	if config.MaxPricePerHour <= 0 {
+		return 0.5, []string{"no price ceiling configured, neutral score"}, nil
+	}
+	score := math.Max(0, (config.MaxPricePerHour-offer.DPH)/config.MaxPricePerHour)
+	return score, []string{fmt.Sprintf("price: %.3f", score)}, nil
+}
+
+// geographicZoneBonusPriority scores an offer by how well its geolocation
+// matches the configured region preferences, favoring North America zones by
+// default and any explicit PreferredRegions match otherwise.
+type geographicZoneBonusPriority struct{}
+
+func (geographicZoneBonusPriority) Name() string { return "geographic-zone-bonus" }
+func (geographicZoneBonusPriority) Weight() float64 { return 0.20 }
+
+func (geographicZoneBonusPriority) Score(ctx context.Context, offer api.InstanceOffer, _ *corev1.Pod) (float64, []string, error) {
+	config := configFromContext(ctx)
+	location := strings.ToUpper(offer.Geolocation)
+
+	switch {
+	case strings.HasPrefix(location, "US-CA"), strings.HasPrefix(location, "US-WA"), strings.HasPrefix(location, "US-OR"):
+		return 1.0, []string{"US West Coast"}, nil
+	case strings.HasPrefix(location, "US-TX"), strings.HasPrefix(location, "US-CO"), strings.HasPrefix(location, "US-AZ"):
+		return 0.75, []string{"US Central"}, nil
+	case strings.HasPrefix(location, "US-NY"), strings.HasPrefix(location, "US-FL"), strings.HasPrefix(location, "US-VA"):
+		return 0.5, []string{"US East Coast"}, nil
+	case strings.HasPrefix(location, "CA-"):
+		return 0.4, []string{"Canada"}, nil
+	case location == "US":
+		return 0.3, []string{"US general"}, nil
+	case len(config.PreferredRegions) > 0 && contains(config.PreferredRegions, location):
+		return 0.3, []string{"preferred region match"}, nil
+	default:
+		return 0.1, []string{"non-preferred region"}, nil
+	}
+}
+
+// verifiedHostPriority rewards verified hosts with an established run time,
+// both signals Vast.ai offers of how trustworthy a host is likely to be.
+type verifiedHostPriority struct{}
+
+func (verifiedHostPriority) Name() string { return "verified-host" }
+func (verifiedHostPriority) Weight() float64 { return 0.10 }
+
+func (verifiedHostPriority) Score(_ context.Context, offer api.InstanceOffer, _ *corev1.Pod) (float64, []string, error) {
+	const minMatureRuntimeSeconds = 30 * 24 * 3600 // 30 days
+
+	score := 0.3
+	var notes []string
+
+	if offer.Verified {
+		score += 0.5
+		notes = append(notes, "verified host")
+	}
+	if offer.HostRunTime >= minMatureRuntimeSeconds {
+		score += 0.2
+		notes = append(notes, "established host")
+	} else {
+		notes = append(notes, "new host")
+	}
+
+	return math.Min(1.0, score), notes, nil
+}
+
+// bandwidthPriority normalizes combined down/up throughput against a
+// reasonable ceiling rather than a flat pass/fail bonus.
+type bandwidthPriority struct{}
+
+func (bandwidthPriority) Name() string { return "bandwidth" }
+func (bandwidthPriority) Weight() float64 { return 0.08 }
+
+func (bandwidthPriority) Score(_ context.Context, offer api.InstanceOffer, _ *corev1.Pod) (float64, []string, error) {
+	const bandwidthCeilingMbps = 500.0
+	score := math.Min(1.0, float64(offer.InetDown+offer.InetUp)/(2*bandwidthCeilingMbps))
+	return score, []string{fmt.Sprintf("bandwidth: %.3f", score)}, nil
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ExtenderConfig declares one HTTP-callable external scorer. Extenders let an
+// operator plug in company-specific scoring (e.g. "prefer hosts in our own
+// colo") without recompiling the provider.
+type ExtenderConfig struct {
+	Name      string  `json:"name"`
+	URL       string  `json:"url"`
+	Weight    float64 `json:"weight"`
+	TimeoutMS int     `json:"timeoutMS"`
+}
+
+// SchedulingProfile lists the predicates and priorities a scheduler run should
+// use and their weights, similar to a kube-scheduler profile. Several profiles
+// (e.g. "cost-optimized", "latency-optimized") can be defined; an
+// InstanceScheduler runs one at a time, set with SetProfile. Node pool entries
+// do not select a profile, so a scheduler runs DefaultProfile unless
+// SetProfile is called.
+type SchedulingProfile struct {
+	Name              string             `json:"name"`
+	EnabledPredicates []string           `json:"enabledPredicates"`
+	EnabledPriorities []string           `json:"enabledPriorities"`
+	PriorityWeights   map[string]float64 `json:"priorityWeights,omitempty"`
+	Extenders         []ExtenderConfig   `json:"extenders,omitempty"`
+}
+
+// DefaultProfile enables every built-in plugin at its default weight and adds
+// no extenders; it reproduces the scoring behavior the scheduler has always
+// shipped with.
+func DefaultProfile() SchedulingProfile {
+	return SchedulingProfile{
+		Name:              "default",
+		EnabledPredicates: []string{"gpu-match", "price-ceiling", "min-reliability", "region-allowlist", "latency-ceiling"},
+		EnabledPriorities: []string{"latency", "price", "geographic-zone-bonus", "verified-host", "bandwidth"},
+	}
+}
+
+// LoadSchedulingProfile reads and parses a SchedulingProfile document, the format a
+// ConfigMap-mounted profile file is expected to be in.
+func LoadSchedulingProfile(path string) (*SchedulingProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduling profile %s: %w", path, err)
+	}
+
+	var profile SchedulingProfile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse scheduling profile %s: %w", path, err)
+	}
+
+	if profile.Name == "" {
+		return nil, fmt.Errorf("scheduling profile %s declares no name", path)
+	}
+	if len(profile.EnabledPriorities) == 0 {
+		return nil, fmt.Errorf("scheduling profile %s enables no priorities", path)
+	}
+
+	return &profile, nil
+}
@@ -0,0 +1,502 @@
+// Package scheduler selects which Vast.ai offer to launch a pod on.
+//
+// Selection runs in two phases, mirroring how kube-scheduler organizes
+// predicates (hard filters) and priorities (soft scorers): Predicates drop
+// offers that can't work at all, then Priorities rank the offers that
+// remain. Both phases are pluggable - see plugin.go for the Predicate and
+// Priority interfaces and PluginRegistry, and predicates.go/priorities.go for
+// the built-ins. A SchedulingProfile (profile.go) selects which plugins run
+// and at what weight, and may add HTTP extenders for scoring logic the
+// provider doesn't ship with.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/latency"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/metrics"
+)
+
+// ErrNoOffersFound is returned by FindBestOffer when Vast.ai's search API has
+// no candidates matching the configured criteria at all.
+var ErrNoOffersFound = errors.New("no instances found matching criteria")
+
+// ErrBudgetCapExceeded is returned by FindBestOffer when launching another
+// instance would push cumulative spend past SchedulerConfig.MaxTotalSpendPerHour.
+var ErrBudgetCapExceeded = errors.New("launching another instance would exceed the configured spend cap")
+
+// InstanceScheduler handles intelligent selection of Vast.ai instances
+type InstanceScheduler struct {
+	client          *api.VastClient
+	config          *SchedulerConfig
+	profile         SchedulingProfile
+	registry        *PluginRegistry
+	httpClient      *http.Client
+	latencyProvider latency.LatencyProvider
+
+	// kubeClient, nodeName, endpoints, and recorder support PreemptForPod
+	// (preempt.go); preemption stays disabled until EnablePreemption is called.
+	kubeClient kubernetes.Interface
+	nodeName   string
+	endpoints  EndpointDrainer
+	recorder   record.EventRecorder
+
+	// offerTemplates resolves a pod's vast.ozzu.io/offer-template annotation
+	// into search-criteria overrides (offertemplate.go); nil disables the
+	// feature entirely.
+	offerTemplates OfferTemplateProvider
+}
+
+// SchedulerConfig holds the search criteria and predicate thresholds used to
+// pick an instance. Which priorities run and how they're weighted is
+// controlled separately by a SchedulingProfile.
+type SchedulerConfig struct {
+	// GPU requirements
+	GPUType        string
+	MinGPUMemoryGB int
+
+	// Performance requirements
+	MaxPricePerHour float64
+	MinReliability  float64
+	MinDownloadMbps int
+	MinUploadMbps   int
+	VerifiedOnly    bool
+
+	// Geographic preferences (North America optimized)
+	PreferredRegions []string
+	FallbackRegions  []string
+	BlockedRegions   []string
+
+	// Latency optimization
+	MaxLatencyMS        int
+	LatencyCheckEnabled bool
+
+	// MinScoreThreshold is the lowest combined score an offer may have and still be
+	// launched; offers scoring below this are treated as "no match" so a bad-but-only
+	// offer doesn't get picked just because it's first.
+	MinScoreThreshold float64
+
+	// MaxTotalSpendPerHour caps the combined $/hr of every running instance this
+	// scheduler is allowed to carry; 0 disables the cap. FindBestOffer fails with
+	// ErrBudgetCapExceeded once launching another instance would cross it.
+	MaxTotalSpendPerHour float64
+
+	// PreemptionPolicy is the default preemption behavior for namespaces with no
+	// entry in NamespacePreemptionPolicies. See preempt.go.
+	PreemptionPolicy PreemptionPolicy
+	// NamespacePreemptionPolicies overrides PreemptionPolicy per namespace.
+	NamespacePreemptionPolicies map[string]PreemptionPolicy
+	// PreemptionPriorityThreshold is the priority ceiling PreemptionAnyBelowThreshold
+	// evicts under, independent of the incoming pod's own priority.
+	PreemptionPriorityThreshold int32
+}
+
+// NewInstanceScheduler creates a new scheduler with North America optimized
+// defaults and the default scheduling profile (all built-in plugins enabled).
+func NewInstanceScheduler(client *api.VastClient) *InstanceScheduler {
+	return &InstanceScheduler{
+		client: client,
+		config: &SchedulerConfig{
+			GPUType:             "RTX_3060",
+			MinGPUMemoryGB:      12,
+			MaxPricePerHour:     0.50,
+			MinReliability:      0.95,
+			MinDownloadMbps:     100,
+			MinUploadMbps:       100,
+			VerifiedOnly:        true,
+			PreferredRegions:    []string{"US", "CA", "MX"}, // North America
+			FallbackRegions:     []string{"EU"},
+			BlockedRegions:      []string{"RU", "CN", "KP"},
+			MaxLatencyMS:        50,
+			LatencyCheckEnabled: true,
+			MinScoreThreshold:   0.3,
+		},
+		profile:         DefaultProfile(),
+		registry:        DefaultRegistry(),
+		httpClient:      &http.Client{},
+		latencyProvider: latency.NewCachingProvider(latency.NewTCPProbeProvider(), latency.DefaultCacheSize, latency.DefaultCacheTTL),
+	}
+}
+
+// SetLatencyProvider replaces the scheduler's LatencyProvider, e.g. to inject
+// a RemoteAgentProvider that measures from a node-local agent instead of
+// probing from wherever this process runs, or a fake one in tests.
+func (s *InstanceScheduler) SetLatencyProvider(provider latency.LatencyProvider) {
+	s.latencyProvider = provider
+}
+
+// SetEndpointDrainer wires the endpoint manager PreemptForPod uses to cut
+// traffic to a preempted pod immediately, rather than waiting for its delete.
+func (s *InstanceScheduler) SetEndpointDrainer(drainer EndpointDrainer) {
+	s.endpoints = drainer
+}
+
+// EnablePreemption wires the Kubernetes client and node name PreemptForPod
+// needs to list running pods and emit Preempted events. Preemption stays
+// disabled - PreemptForPod always fails - until this is called.
+func (s *InstanceScheduler) EnablePreemption(kubeClient kubernetes.Interface, nodeName string) {
+	s.kubeClient = kubeClient
+	s.nodeName = nodeName
+	s.recorder = newSchedulerEventRecorder(kubeClient, nodeName)
+}
+
+// SetConfig replaces the scheduler's search criteria and predicate thresholds,
+// letting a node pool entry override the North-America-optimized defaults
+// with its own region and GPU class.
+func (s *InstanceScheduler) SetConfig(config *SchedulerConfig) {
+	s.config = config
+}
+
+// Config returns the scheduler's current SchedulerConfig, e.g. for the
+// disruption controller to detect drift against what's desired now.
+func (s *InstanceScheduler) Config() *SchedulerConfig {
+	return s.config
+}
+
+// SetProfile replaces the scheduler's SchedulingProfile, changing which
+// plugins run and how they're weighted without touching search criteria.
+func (s *InstanceScheduler) SetProfile(profile SchedulingProfile) {
+	s.profile = profile
+}
+
+// SelectAndLaunchInstance finds the best instance and launches it. If no
+// offer fits - either because nothing matches the search criteria or because
+// launching one would cross MaxTotalSpendPerHour - it follows the
+// kube-scheduler Preempt pattern and tries to free room via PreemptForPod
+// before giving up.
+func (s *InstanceScheduler) SelectAndLaunchInstance(ctx context.Context, pod *corev1.Pod) (*api.Instance, error) {
+	instance, err := s.findAndLaunch(ctx, pod)
+	if err == nil {
+		schedulingAttemptsTotal.WithLabelValues("scheduled").Inc()
+		return instance, nil
+	}
+	if !errors.Is(err, ErrNoOffersFound) && !errors.Is(err, ErrBudgetCapExceeded) {
+		schedulingAttemptsTotal.WithLabelValues("failed").Inc()
+		return nil, err
+	}
+
+	log := klog.FromContext(ctx)
+	log.Info("no fit found, attempting preemption", "pod", pod.Name, "reason", err)
+
+	instance, preemptErr := s.PreemptForPod(ctx, pod)
+	if preemptErr != nil {
+		schedulingAttemptsTotal.WithLabelValues("failed").Inc()
+		return nil, fmt.Errorf("%w (preemption also failed: %v)", err, preemptErr)
+	}
+	schedulingAttemptsTotal.WithLabelValues("preempted").Inc()
+	return instance, nil
+}
+
+// findAndLaunch is SelectAndLaunchInstance without the preemption fallback,
+// used both as its first attempt and by PreemptForPod to retry after evicting
+// a candidate - retrying through SelectAndLaunchInstance itself would recurse.
+func (s *InstanceScheduler) findAndLaunch(ctx context.Context, pod *corev1.Pod) (*api.Instance, error) {
+	bestOffer, err := s.FindBestOffer(ctx, pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select instance: %w", err)
+	}
+
+	instance, err := s.LaunchOffer(ctx, bestOffer, pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instance: %w", err)
+	}
+
+	s.recordOfferTemplateMatch(ctx, pod, bestOffer.Offer)
+	return instance, nil
+}
+
+// FindBestOffer searches for and scores candidate offers without launching
+// one, so a caller can compare the result against a running instance - as the
+// disruption controller does for consolidation - before committing to it.
+func (s *InstanceScheduler) FindBestOffer(ctx context.Context, pod *corev1.Pod) (*api.InstanceScore, error) {
+	log := klog.FromContext(ctx)
+	log.Info(fmt.Sprintf("Selecting Vast.ai instance using scheduling profile %q", s.profile.Name))
+
+	if s.config.MaxTotalSpendPerHour > 0 {
+		running, err := s.runningInstances(ctx)
+		if err != nil {
+			log.Info(fmt.Sprintf("failed to check spend cap, proceeding without it: %v", err))
+		} else {
+			var total float64
+			byNamespace := make(map[string]float64)
+			for _, r := range running {
+				total += r.instance.DPH
+				byNamespace[r.pod.Namespace] += r.instance.DPH
+			}
+			// Sampled here, the same cadence the spend cap itself is
+			// checked, rather than on every scheduling pass regardless of
+			// whether MaxTotalSpendPerHour is configured.
+			for namespace, dph := range byNamespace {
+				metrics.SetNamespaceHourlySpend(namespace, dph)
+			}
+			if total+s.config.MaxPricePerHour > s.config.MaxTotalSpendPerHour {
+				return nil, ErrBudgetCapExceeded
+			}
+		}
+	}
+
+	// Build search criteria
+	criteria := api.SearchCriteria{
+		GPUType:          s.config.GPUType,
+		MinGPUMemoryGB:   s.config.MinGPUMemoryGB,
+		MaxPricePerHour:  s.config.MaxPricePerHour,
+		MinReliability:   s.config.MinReliability,
+		MinDownloadMbps:  s.config.MinDownloadMbps,
+		MinUploadMbps:    s.config.MinUploadMbps,
+		PreferredRegions: s.config.PreferredRegions,
+		VerifiedOnly:     s.config.VerifiedOnly,
+		RentableOnly:     true,
+	}
+
+	gpuGlob := s.applyOfferTemplate(ctx, pod, &criteria)
+	region := regionLabel(criteria.PreferredRegions)
+
+	// Search for available instances
+	searchStart := time.Now()
+	offers, err := s.client.SearchInstances(ctx, criteria)
+	if err != nil {
+		metrics.ObserveSearchDuration("error", criteria.GPUType, region, searchStart)
+		return nil, fmt.Errorf("failed to search instances: %w", err)
+	}
+
+	offers = filterByGPUModelGlob(offers, gpuGlob)
+
+	if len(offers) == 0 {
+		metrics.ObserveSearchDuration("no-offers", criteria.GPUType, region, searchStart)
+		return nil, ErrNoOffersFound
+	}
+	metrics.ObserveSearchDuration("success", criteria.GPUType, region, searchStart)
+
+	log.Info(fmt.Sprintf("Found %d instance offers, scoring with profile %q...", len(offers), s.profile.Name))
+
+	bestOffer, err := s.selectBestInstance(ctx, offers, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(fmt.Sprintf("Best candidate: instance %d (Score: %.3f) in %s for $%.3f/hr",
+		bestOffer.Offer.ID, bestOffer.Score, bestOffer.Offer.Geolocation, bestOffer.Offer.DPH))
+
+	return bestOffer, nil
+}
+
+// LaunchOffer creates and waits for an instance from a previously found offer.
+func (s *InstanceScheduler) LaunchOffer(ctx context.Context, offer *api.InstanceScore, pod *corev1.Pod) (*api.Instance, error) {
+	log := klog.FromContext(ctx)
+
+	createStart := time.Now()
+	instance, err := s.client.CreateInstance(ctx, offer.Offer, pod, s.nodeName)
+	if err != nil {
+		metrics.ObserveCreateInstanceDuration("error", s.config.GPUType, offer.Offer.Geolocation, createStart)
+		return nil, err
+	}
+	metrics.ObserveCreateInstanceDuration("success", s.config.GPUType, offer.Offer.Geolocation, createStart)
+
+	log.Info(fmt.Sprintf("Instance %d launched successfully at %s", instance.ID, instance.PublicIP))
+	return instance, nil
+}
+
+// selectBestInstance runs the profile's predicates to shrink the offer set,
+// then scores survivors with its priorities and extenders to pick a winner.
+func (s *InstanceScheduler) selectBestInstance(ctx context.Context, offers []api.InstanceOffer, pod *corev1.Pod) (*api.InstanceScore, error) {
+	ctx = withConfig(ctx, s.config)
+	ctx = withLatencyProvider(ctx, s.latencyProvider)
+
+	scoringStart := time.Now()
+	region := regionLabel(s.config.PreferredRegions)
+
+	predicates, err := s.registry.Predicates(s.profile.EnabledPredicates)
+	if err != nil {
+		return nil, fmt.Errorf("scheduling profile %q: %w", s.profile.Name, err)
+	}
+	priorities, err := s.registry.Priorities(s.profile.EnabledPriorities, s.profile.PriorityWeights)
+	if err != nil {
+		return nil, fmt.Errorf("scheduling profile %q: %w", s.profile.Name, err)
+	}
+
+	survivors := s.runPredicates(ctx, predicates, offers, pod)
+	if len(survivors) == 0 {
+		metrics.ObserveScoringDuration("no-survivors", s.config.GPUType, region, scoringStart)
+		err := fmt.Errorf("no instances passed predicates %v", s.profile.EnabledPredicates)
+		s.recordDecision(pod, nil, 0, err.Error())
+		return nil, err
+	}
+
+	scores := s.runPriorities(ctx, priorities, survivors, pod)
+
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	top := scores
+	if len(top) > 3 {
+		top = top[:3]
+	}
+	candidates := make([]metrics.CandidateSummary, len(top))
+	for i, score := range top {
+		klog.Info(fmt.Sprintf("Candidate %d: Instance %d, Score %.3f, %s, $%.3f/hr, %s",
+			i+1, score.Offer.ID, score.Score, score.Offer.Geolocation,
+			score.Offer.DPH, strings.Join(score.Notes, ", ")))
+		candidates[i] = metrics.CandidateSummary{
+			InstanceID:   score.Offer.ID,
+			Score:        score.Score,
+			Region:       score.Offer.Geolocation,
+			PricePerHour: score.Offer.DPH,
+			Notes:        score.Notes,
+		}
+	}
+
+	if scores[0].Score < s.config.MinScoreThreshold {
+		metrics.ObserveScoringDuration("below-threshold", s.config.GPUType, region, scoringStart)
+		err := fmt.Errorf("best candidate (instance %d, score %.3f) is below minimum threshold %.3f",
+			scores[0].Offer.ID, scores[0].Score, s.config.MinScoreThreshold)
+		s.recordDecision(pod, candidates, 0, err.Error())
+		return nil, err
+	}
+
+	metrics.ObserveScoringDuration("success", s.config.GPUType, region, scoringStart)
+	s.recordDecision(pod, candidates, scores[0].Offer.ID, "")
+	return &scores[0], nil
+}
+
+// recordDecision appends this selectBestInstance call's outcome to the
+// debug ring buffer metrics.RecordDecision serves via /debug/scheduling.
+func (s *InstanceScheduler) recordDecision(pod *corev1.Pod, candidates []metrics.CandidateSummary, chosen int, reason string) {
+	metrics.RecordDecision(metrics.SchedulingDecision{
+		Time:       time.Now(),
+		Pod:        pod.Namespace + "/" + pod.Name,
+		Profile:    s.profile.Name,
+		Candidates: candidates,
+		Chosen:     chosen,
+		Reason:     reason,
+	})
+}
+
+// regionLabel returns the first preferred region for metric labeling, or
+// "unspecified" when the scheduler has no region preference configured.
+func regionLabel(preferredRegions []string) string {
+	if len(preferredRegions) == 0 {
+		return "unspecified"
+	}
+	return preferredRegions[0]
+}
+
+// runPredicates filters offers in parallel, logging each plugin's rejection
+// reason. An offer survives only if every predicate passes it.
+func (s *InstanceScheduler) runPredicates(ctx context.Context, predicates []Predicate, offers []api.InstanceOffer, pod *corev1.Pod) []api.InstanceOffer {
+	results := make([]bool, len(offers))
+
+	var wg sync.WaitGroup
+	for i, offer := range offers {
+		wg.Add(1)
+		go func(i int, offer api.InstanceOffer) {
+			defer wg.Done()
+			for _, predicate := range predicates {
+				ok, reason, err := predicate.Filter(ctx, offer, pod)
+				if err != nil {
+					klog.Info(fmt.Sprintf("predicate %s errored on instance %d: %v, treating as rejected", predicate.Name(), offer.ID, err))
+					metrics.IncOffersFiltered(predicate.Name())
+					return
+				}
+				if !ok {
+					klog.Info(fmt.Sprintf("instance %d rejected by predicate %s: %s", offer.ID, predicate.Name(), reason))
+					metrics.IncOffersFiltered(predicate.Name())
+					return
+				}
+			}
+			results[i] = true
+		}(i, offer)
+	}
+	wg.Wait()
+
+	survivors := make([]api.InstanceOffer, 0, len(offers))
+	for i, offer := range offers {
+		if results[i] {
+			survivors = append(survivors, offer)
+		}
+	}
+	return survivors
+}
+
+// runPriorities scores offers in parallel, combining each offer's priority
+// scores and extender scores into a single InstanceScore.
+func (s *InstanceScheduler) runPriorities(ctx context.Context, priorities []Priority, offers []api.InstanceOffer, pod *corev1.Pod) []api.InstanceScore {
+	scores := make([]api.InstanceScore, len(offers))
+
+	var wg sync.WaitGroup
+	for i, offer := range offers {
+		wg.Add(1)
+		go func(i int, offer api.InstanceOffer) {
+			defer wg.Done()
+			scores[i] = s.scoreOffer(ctx, priorities, offer, pod)
+		}(i, offer)
+	}
+	wg.Wait()
+
+	return scores
+}
+
+// scoreOffer combines the weighted, normalized output of every priority with
+// any configured extenders' scores into a single InstanceScore.
+func (s *InstanceScheduler) scoreOffer(ctx context.Context, priorities []Priority, offer api.InstanceOffer, pod *corev1.Pod) api.InstanceScore {
+	var (
+		weightedSum float64
+		totalWeight float64
+		notes       []string
+	)
+
+	for _, priority := range priorities {
+		score, priorityNotes, err := priority.Score(ctx, offer, pod)
+		if err != nil {
+			klog.Info(fmt.Sprintf("priority %s errored on instance %d: %v, skipping", priority.Name(), offer.ID, err))
+			continue
+		}
+		weightedSum += score * priority.Weight()
+		totalWeight += priority.Weight()
+		notes = append(notes, priorityNotes...)
+	}
+
+	score := 0.0
+	if totalWeight > 0 {
+		score = weightedSum / totalWeight
+	}
+
+	for _, extender := range s.profile.Extenders {
+		extenderScore, extenderNotes, err := callExtender(ctx, s.httpClient, extender, offer, pod)
+		if err != nil {
+			klog.Info(fmt.Sprintf("extender %s errored on instance %d: %v, skipping", extender.Name, offer.ID, err))
+			continue
+		}
+		score += extenderScore
+		notes = append(notes, extenderNotes...)
+	}
+
+	return api.InstanceScore{
+		Offer: offer,
+		Score: score,
+		Notes: notes,
+	}
+}
+
+// contains reports whether slice contains item, case-insensitively.
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+	return false
+}
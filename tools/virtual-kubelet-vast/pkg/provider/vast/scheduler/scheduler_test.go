@@ -0,0 +1,228 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+	"github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/latency"
+)
+
+type fakeLatencyProvider struct {
+	rtt time.Duration
+	err error
+}
+
+func (f fakeLatencyProvider) Measure(context.Context, string, latency.Target) (time.Duration, error) {
+	return f.rtt, f.err
+}
+
+func TestRegionAllowlistPredicate_RejectsBlockedRegion(t *testing.T) {
+	ctx := withConfig(context.Background(), &SchedulerConfig{BlockedRegions: []string{"RU", "CN"}})
+	offer := api.InstanceOffer{Geolocation: "RU-MOW"}
+
+	ok, reason, err := regionAllowlistPredicate{}.Filter(ctx, offer, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if ok {
+		t.Fatalf("Filter() = ok, want rejected, reason %q", reason)
+	}
+}
+
+func TestRegionAllowlistPredicate_AllowsUnblockedRegion(t *testing.T) {
+	ctx := withConfig(context.Background(), &SchedulerConfig{BlockedRegions: []string{"RU", "CN"}})
+	offer := api.InstanceOffer{Geolocation: "US-CA"}
+
+	ok, _, err := regionAllowlistPredicate{}.Filter(ctx, offer, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if !ok {
+		t.Fatal("Filter() = rejected, want allowed")
+	}
+}
+
+func TestPriceCeilingPredicate(t *testing.T) {
+	ctx := withConfig(context.Background(), &SchedulerConfig{MaxPricePerHour: 0.5})
+
+	ok, _, err := priceCeilingPredicate{}.Filter(ctx, api.InstanceOffer{DPH: 0.6}, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if ok {
+		t.Fatal("Filter() = ok for offer above ceiling, want rejected")
+	}
+
+	ok, _, err = priceCeilingPredicate{}.Filter(ctx, api.InstanceOffer{DPH: 0.3}, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if !ok {
+		t.Fatal("Filter() = rejected for offer under ceiling, want ok")
+	}
+}
+
+func TestLatencyCeilingPredicate_RejectsOverBudget(t *testing.T) {
+	ctx := withConfig(context.Background(), &SchedulerConfig{LatencyCheckEnabled: true, MaxLatencyMS: 50})
+	ctx = withLatencyProvider(ctx, fakeLatencyProvider{rtt: 100 * time.Millisecond})
+
+	ok, reason, err := latencyCeilingPredicate{}.Filter(ctx, api.InstanceOffer{}, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if ok {
+		t.Fatalf("Filter() = ok, want rejected, reason %q", reason)
+	}
+}
+
+func TestLatencyCeilingPredicate_AllowsUnderBudget(t *testing.T) {
+	ctx := withConfig(context.Background(), &SchedulerConfig{LatencyCheckEnabled: true, MaxLatencyMS: 50})
+	ctx = withLatencyProvider(ctx, fakeLatencyProvider{rtt: 10 * time.Millisecond})
+
+	ok, _, err := latencyCeilingPredicate{}.Filter(ctx, api.InstanceOffer{}, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if !ok {
+		t.Fatal("Filter() = rejected, want allowed")
+	}
+}
+
+func TestLatencyCeilingPredicate_DisabledIsNoop(t *testing.T) {
+	ctx := withConfig(context.Background(), &SchedulerConfig{LatencyCheckEnabled: false, MaxLatencyMS: 1})
+	ctx = withLatencyProvider(ctx, fakeLatencyProvider{rtt: time.Hour})
+
+	ok, _, err := latencyCeilingPredicate{}.Filter(ctx, api.InstanceOffer{}, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if !ok {
+		t.Fatal("Filter() = rejected while LatencyCheckEnabled is false, want allowed (no-op)")
+	}
+}
+
+func TestLatencyPriority_ScoresLowerLatencyHigher(t *testing.T) {
+	config := &SchedulerConfig{LatencyCheckEnabled: true, MaxLatencyMS: 100}
+
+	ctx := withLatencyProvider(withConfig(context.Background(), config), fakeLatencyProvider{rtt: 10 * time.Millisecond})
+	fast, _, err := latencyPriority{}.Score(ctx, api.InstanceOffer{}, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+
+	ctx = withLatencyProvider(withConfig(context.Background(), config), fakeLatencyProvider{rtt: 90 * time.Millisecond})
+	slow, _, err := latencyPriority{}.Score(ctx, api.InstanceOffer{}, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+
+	if fast <= slow {
+		t.Errorf("Score() fast-offer=%.3f, slow-offer=%.3f, want fast > slow", fast, slow)
+	}
+}
+
+func TestPodPriority_DefaultsToZero(t *testing.T) {
+	if got := podPriority(&corev1.Pod{}); got != 0 {
+		t.Errorf("podPriority() = %d, want 0 for pod with no Spec.Priority", got)
+	}
+
+	var priority int32 = 42
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Priority: &priority}}
+	if got := podPriority(pod); got != priority {
+		t.Errorf("podPriority() = %d, want %d", got, priority)
+	}
+}
+
+func TestPreemptionPolicy_NamespaceOverrideWinsOverDefault(t *testing.T) {
+	s := &InstanceScheduler{
+		config: &SchedulerConfig{
+			PreemptionPolicy: PreemptionNever,
+			NamespacePreemptionPolicies: map[string]PreemptionPolicy{
+				"batch": PreemptionLowerPriorityOnly,
+			},
+		},
+	}
+
+	if got := s.preemptionPolicy("batch"); got != PreemptionLowerPriorityOnly {
+		t.Errorf("preemptionPolicy(%q) = %q, want %q", "batch", got, PreemptionLowerPriorityOnly)
+	}
+	if got := s.preemptionPolicy("default"); got != PreemptionNever {
+		t.Errorf("preemptionPolicy(%q) = %q, want fallback %q", "default", got, PreemptionNever)
+	}
+}
+
+func TestPreemptForPod_DisabledPolicyFailsFast(t *testing.T) {
+	s := &InstanceScheduler{
+		config: &SchedulerConfig{PreemptionPolicy: PreemptionNever},
+	}
+
+	if _, err := s.PreemptForPod(context.Background(), &corev1.Pod{}); err == nil {
+		t.Fatal("PreemptForPod() error = nil, want error when policy is PreemptionNever")
+	}
+}
+
+func TestJoinInstancesToPods_MatchesByLabelUID(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "pod-uid-1", Namespace: "default", Name: "gpu-job"}}
+	instances := []api.Instance{
+		{ID: 1, Label: api.FormatInstanceLabel(api.InstanceLabel{UID: "pod-uid-1", Node: "vast-node", Pod: "default/gpu-job"})},
+		{ID: 2, Label: "not-a-valid-label"},
+		{ID: 3, Label: api.FormatInstanceLabel(api.InstanceLabel{UID: "pod-uid-missing", Node: "vast-node", Pod: "default/gone"})},
+	}
+
+	running := joinInstancesToPods(instances, []*corev1.Pod{pod})
+	if len(running) != 1 {
+		t.Fatalf("joinInstancesToPods() returned %d entries, want 1", len(running))
+	}
+	if running[0].instance.ID != 1 || running[0].pod != pod {
+		t.Errorf("joinInstancesToPods() = %+v, want instance 1 joined to %v", running[0], pod)
+	}
+}
+
+func TestFilterByGPUModelGlob(t *testing.T) {
+	offers := []api.InstanceOffer{
+		{ID: 1, GPUName: "RTX_4090"},
+		{ID: 2, GPUName: "RTX_3060"},
+		{ID: 3, GPUName: "A100_PCIE"},
+	}
+
+	filtered := filterByGPUModelGlob(offers, "RTX_*")
+	if len(filtered) != 2 {
+		t.Fatalf("filterByGPUModelGlob() returned %d offers, want 2", len(filtered))
+	}
+
+	if got := filterByGPUModelGlob(offers, ""); len(got) != len(offers) {
+		t.Errorf("filterByGPUModelGlob() with empty glob = %d offers, want all %d", len(got), len(offers))
+	}
+}
+
+func TestSelectBestInstance_PredicatesFilterBeforePriorities(t *testing.T) {
+	s := &InstanceScheduler{
+		config: &SchedulerConfig{
+			MaxPricePerHour:   1.0,
+			MinReliability:    0.9,
+			MinScoreThreshold: 0,
+		},
+		profile:    DefaultProfile(),
+		registry:   DefaultRegistry(),
+		httpClient: nil,
+	}
+
+	offers := []api.InstanceOffer{
+		{ID: 1, DPH: 2.0, Reliability: 0.99, Geolocation: "US-CA"}, // rejected: over price ceiling
+		{ID: 2, DPH: 0.5, Reliability: 0.99, Geolocation: "US-CA"}, // survives, best zone
+		{ID: 3, DPH: 0.5, Reliability: 0.99, Geolocation: "DE"},    // survives, worse zone
+	}
+
+	best, err := s.selectBestInstance(context.Background(), offers, &corev1.Pod{})
+	if err != nil {
+		t.Fatalf("selectBestInstance() error = %v", err)
+	}
+	if best.Offer.ID != 2 {
+		t.Errorf("selectBestInstance() picked instance %d, want 2 (cheaper, better zone, passes predicates)", best.Offer.ID)
+	}
+}
@@ -0,0 +1,308 @@
+package vast
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	vapi "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+const (
+	// DefaultStateReconcileInterval is how often the state reconciler
+	// re-derives p.instances from Vast.ai and persists it, independent of
+	// the orphan-GC Reconciler's much slower sweep.
+	DefaultStateReconcileInterval = time.Minute
+
+	// VastInstanceLostReason is the pod condition/container status reason
+	// set when a tracked instance disappears from Vast.ai out-of-band
+	// (billing, host reclaim) instead of being destroyed by DeletePod.
+	VastInstanceLostReason = "VastInstanceLost"
+
+	// stateConfigMapKeyPrefix namespaces pod-name keys within the state
+	// ConfigMap's Data map, in case other fields are ever added alongside it.
+	stateConfigMapKeyPrefix = "pod-"
+)
+
+// instanceStateReconciler rebuilds VastProvider.instances from Vast.ai's view
+// of the world and a ConfigMap snapshot, so that a process restart or an
+// instance disappearing out-of-band doesn't leave a pod stuck reporting
+// stale status forever. It complements Reconciler (orphan-instance GC):
+// this type recovers and corrects in-memory state, Reconciler destroys
+// instances whose pods are gone.
+type instanceStateReconciler struct {
+	provider   *VastProvider
+	client     *vapi.VastClient
+	kubeClient kubernetes.Interface
+	nodeName   string
+	interval   time.Duration
+}
+
+// newInstanceStateReconciler creates a state reconciler for provider's node.
+func newInstanceStateReconciler(provider *VastProvider, client *vapi.VastClient, kubeClient kubernetes.Interface, nodeName string) *instanceStateReconciler {
+	return &instanceStateReconciler{
+		provider:   provider,
+		client:     client,
+		kubeClient: kubeClient,
+		nodeName:   nodeName,
+		interval:   DefaultStateReconcileInterval,
+	}
+}
+
+// Run recovers provider's in-memory state once on startup, then reconciles it
+// against Vast.ai on a fixed interval until ctx is cancelled.
+func (r *instanceStateReconciler) Run(ctx context.Context) {
+	log := klog.FromContext(ctx).WithValues("component", "vast-state-reconciler")
+	log.Info("Recovering instance state from Vast.ai and persisted ConfigMap")
+
+	if err := r.recoverOnStartup(ctx); err != nil {
+		log.Error(err, "failed to recover instance state on startup")
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.reconcileOnce(ctx); err != nil {
+				log.Error(err, "state reconciliation pass failed")
+			}
+		}
+	}
+}
+
+// recoverOnStartup repopulates provider.instances for pods that are still
+// scheduled to this node, trusting the persisted ConfigMap for pod->instance
+// identity and Vast.ai's instance list for whether that instance is still
+// alive. It's a no-op (not an error) the first time this node ever runs,
+// when the ConfigMap doesn't exist yet.
+func (r *instanceStateReconciler) recoverOnStartup(ctx context.Context) error {
+	cm, err := r.getConfigMap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load state ConfigMap: %w", err)
+	}
+
+	instances, err := r.client.ListInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list vast instances: %w", err)
+	}
+	instancesByID := make(map[int]vapi.Instance, len(instances))
+	for _, instance := range instances {
+		instancesByID[instance.ID] = instance
+	}
+
+	pods, err := r.podsOnNode(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list pods on node %s: %w", r.nodeName, err)
+	}
+
+	r.provider.mu.Lock()
+	defer r.provider.mu.Unlock()
+
+	for key, idStr := range cm.Data {
+		podName := podNameFromKey(key)
+		if podName == "" || pods[podName] == nil {
+			continue
+		}
+		if _, tracked := r.provider.instances[podName]; tracked {
+			continue
+		}
+
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			continue
+		}
+		instance, alive := instancesByID[id]
+		if !alive {
+			continue
+		}
+
+		copied := instance
+		r.provider.instances[podName] = &copied
+	}
+
+	return nil
+}
+
+// reconcileOnce re-derives provider.instances from Vast.ai's current instance
+// list: instances that vanished are marked VastInstanceLostReason, and
+// instances the label identifies as belonging to a still-scheduled pod but
+// that provider.instances doesn't yet know about are adopted (covering a
+// restart between recoverOnStartup and the ConfigMap's last persist). The
+// result is persisted back to the ConfigMap.
+func (r *instanceStateReconciler) reconcileOnce(ctx context.Context) error {
+	instances, err := r.client.ListInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list vast instances: %w", err)
+	}
+	instancesByID := make(map[int]vapi.Instance, len(instances))
+	for _, instance := range instances {
+		instancesByID[instance.ID] = instance
+	}
+
+	pods, err := r.podsOnNode(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list pods on node %s: %w", r.nodeName, err)
+	}
+
+	r.provider.mu.RLock()
+	lost := make([]string, 0)
+	for podName, instance := range r.provider.instances {
+		if _, alive := instancesByID[instance.ID]; !alive {
+			lost = append(lost, podName)
+		}
+	}
+	r.provider.mu.RUnlock()
+
+	for _, podName := range lost {
+		r.markInstanceLost(podName, pods[podName])
+	}
+
+	r.provider.mu.Lock()
+	for _, instance := range instances {
+		label, ok := vapi.ParseInstanceLabel(instance.Label)
+		if !ok || label.Node != r.nodeName {
+			continue
+		}
+		podName := podNameForInstance(label)
+		if podName == "" || pods[podName] == nil {
+			continue
+		}
+		if _, tracked := r.provider.instances[podName]; tracked {
+			continue
+		}
+		copied := instance
+		r.provider.instances[podName] = &copied
+	}
+	r.provider.mu.Unlock()
+
+	return r.persist(ctx)
+}
+
+// markInstanceLost drops podName from provider.instances and records a
+// VastInstanceLostReason status for it, so GetPod/GetPodStatus reports it
+// Failed instead of silently going stale.
+func (r *instanceStateReconciler) markInstanceLost(podName string, pod *corev1.Pod) {
+	cause := fmt.Errorf("instance for pod %s is no longer present on Vast.ai", podName)
+
+	r.provider.mu.Lock()
+	delete(r.provider.instances, podName)
+	r.provider.failedPods[podName] = failedPodStatus(pod, VastInstanceLostReason, cause)
+	r.provider.mu.Unlock()
+
+	if pod != nil {
+		r.provider.statusMgr.Untrack(podName)
+		r.provider.disruption.Forget(podName)
+	}
+}
+
+// podsOnNode lists pods scheduled to this reconciler's node, keyed by name.
+func (r *instanceStateReconciler) podsOnNode(ctx context.Context) (map[string]*corev1.Pod, error) {
+	list, err := r.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + r.nodeName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make(map[string]*corev1.Pod, len(list.Items))
+	for i := range list.Items {
+		pod := &list.Items[i]
+		pods[pod.Name] = pod
+	}
+	return pods, nil
+}
+
+// persist snapshots provider.instances into the node's state ConfigMap.
+func (r *instanceStateReconciler) persist(ctx context.Context) error {
+	r.provider.mu.RLock()
+	data := make(map[string]string, len(r.provider.instances))
+	for podName, instance := range r.provider.instances {
+		data[stateConfigMapKeyPrefix+podName] = strconv.Itoa(instance.ID)
+	}
+	r.provider.mu.RUnlock()
+
+	cm, err := r.getConfigMap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load state ConfigMap: %w", err)
+	}
+	cm.Data = data
+
+	_, err = r.kubeClient.CoreV1().ConfigMaps(providerNamespace()).Update(ctx, cm, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to persist state ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// getConfigMap returns the node's state ConfigMap, creating it empty if it
+// doesn't exist yet.
+func (r *instanceStateReconciler) getConfigMap(ctx context.Context) (*corev1.ConfigMap, error) {
+	namespace := providerNamespace()
+	name := configMapName(r.nodeName)
+
+	cm, err := r.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return cm, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	cm = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string]string{},
+	}
+	return r.kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+}
+
+// configMapName is the per-node state ConfigMap's name.
+func configMapName(nodeName string) string {
+	return "vk-vast-state-" + nodeName
+}
+
+// providerNamespace is the namespace the state ConfigMap lives in,
+// overridable via VAST_PROVIDER_NAMESPACE for clusters that don't run this
+// provider's pods in "default" (the namespace the rest of this provider
+// assumes for pods it manages).
+func providerNamespace() string {
+	if ns := os.Getenv("VAST_PROVIDER_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return "default"
+}
+
+// podNameFromKey strips stateConfigMapKeyPrefix from a ConfigMap data key,
+// returning "" if it isn't one of ours.
+func podNameFromKey(key string) string {
+	if !strings.HasPrefix(key, stateConfigMapKeyPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(key, stateConfigMapKeyPrefix)
+}
+
+// podNameForInstance extracts the bare pod name from an InstanceLabel's
+// "<namespace>/<name>" Pod field, since provider.instances is keyed by name
+// alone like the rest of this provider.
+func podNameForInstance(label vapi.InstanceLabel) string {
+	_, name, found := strings.Cut(label.Pod, "/")
+	if !found {
+		return label.Pod
+	}
+	return name
+}
@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultCacheTTL bounds how long a collected sample is trusted before the
+// next GetStatsSummary call re-probes the instance over SSH.
+const DefaultCacheTTL = 10 * time.Second
+
+// nvidiaSMIQuery asks for the fields GetStatsSummary's AcceleratorStats
+// needs plus memory.total, power.draw, and temperature.gpu, which
+// metrics-server doesn't consume today but are cheap to collect in the same
+// SSH round-trip and worth keeping around for /debug/scheduling-style
+// post-mortems.
+const nvidiaSMIQuery = "nvidia-smi --query-gpu=uuid,name,utilization.gpu,memory.used,memory.total,power.draw,temperature.gpu --format=csv,noheader,nounits"
+
+const cgroupStatsCommand = "cat /sys/fs/cgroup/memory/memory.usage_in_bytes /sys/fs/cgroup/cpu/cpuacct.usage 2>/dev/null"
+
+// SSHExecutor runs a single command on a Vast.ai instance over SSH and
+// returns its combined output. api.VastClient satisfies this directly; tests
+// use a mock instead of dialing real SSH.
+type SSHExecutor interface {
+	RunSSHCommand(ctx context.Context, instanceID int, cmd string) (string, error)
+}
+
+type cacheEntry struct {
+	stats   *PodStats
+	expires time.Time
+}
+
+// Collector gathers per-pod container and GPU stats from a Vast.ai instance
+// over SSH, caching results for DefaultCacheTTL per instance so repeated
+// GetStatsSummary calls don't hammer the instance with SSH sessions.
+type Collector struct {
+	exec SSHExecutor
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	cache map[int]cacheEntry // instanceID -> cached stats
+}
+
+// NewCollector builds a Collector that runs commands via exec.
+func NewCollector(exec SSHExecutor) *Collector {
+	return &Collector{
+		exec:  exec,
+		ttl:   DefaultCacheTTL,
+		cache: make(map[int]cacheEntry),
+	}
+}
+
+// Collect returns stats for the pod backed by instanceID, using a cached
+// sample if one younger than DefaultCacheTTL exists. GPU and cgroup reads
+// that fail are logged and left zero-valued rather than failing the whole
+// call: a missing GPU reading shouldn't block the rest of the stats summary.
+// containerName should be the pod's own container name (pod.Spec.Containers[0].Name)
+// so the stats row lines up with the ContainerStatuses this provider reports
+// elsewhere, rather than a name of its own.
+func (c *Collector) Collect(ctx context.Context, podName, namespace, containerName string, startTime time.Time, instanceID int) (*PodStats, error) {
+	if cached, ok := c.cached(instanceID); ok {
+		return cached, nil
+	}
+
+	accelerators, err := c.collectGPU(ctx, instanceID)
+	if err != nil {
+		klog.FromContext(ctx).Info("failed to collect GPU stats, continuing without them", "instanceId", instanceID, "error", err)
+	}
+
+	cpu, mem, err := c.collectCgroup(ctx, instanceID)
+	if err != nil {
+		klog.FromContext(ctx).Info("failed to collect cgroup stats, continuing without them", "instanceId", instanceID, "error", err)
+	}
+
+	podStats := &PodStats{
+		PodName:   podName,
+		Namespace: namespace,
+		StartTime: startTime,
+		Containers: []ContainerStats{
+			{
+				Name:         containerName,
+				CPU:          cpu,
+				Memory:       mem,
+				Accelerators: accelerators,
+			},
+		},
+	}
+
+	c.mu.Lock()
+	c.cache[instanceID] = cacheEntry{stats: podStats, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return podStats, nil
+}
+
+func (c *Collector) cached(instanceID int) (*PodStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache[instanceID]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+// collectGPU runs nvidia-smi and parses its CSV rows into AcceleratorStats,
+// one row per GPU attached to the instance.
+func (c *Collector) collectGPU(ctx context.Context, instanceID int) ([]AcceleratorStats, error) {
+	out, err := c.exec.RunSSHCommand(ctx, instanceID, nvidiaSMIQuery)
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi failed: %w", err)
+	}
+
+	var accelerators []AcceleratorStats
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 7 {
+			continue
+		}
+		duty, _ := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		memUsedMiB, _ := strconv.ParseUint(strings.TrimSpace(fields[3]), 10, 64)
+		memTotalMiB, _ := strconv.ParseUint(strings.TrimSpace(fields[4]), 10, 64)
+		powerDraw, _ := strconv.ParseFloat(strings.TrimSpace(fields[5]), 64)
+		temperature, _ := strconv.ParseFloat(strings.TrimSpace(fields[6]), 64)
+
+		accelerators = append(accelerators, AcceleratorStats{
+			UUID:             strings.TrimSpace(fields[0]),
+			Model:            strings.TrimSpace(fields[1]),
+			DutyCycle:        duty,
+			MemoryUsedBytes:  memUsedMiB * 1024 * 1024,
+			MemoryTotalBytes: memTotalMiB * 1024 * 1024,
+			PowerDrawWatts:   powerDraw,
+			TemperatureC:     temperature,
+		})
+	}
+	return accelerators, nil
+}
+
+// collectCgroup reads the container's cumulative memory and CPU usage
+// counters from cgroup v1, the hierarchy Vast.ai's host images use.
+func (c *Collector) collectCgroup(ctx context.Context, instanceID int) (CPUStats, MemoryStats, error) {
+	out, err := c.exec.RunSSHCommand(ctx, instanceID, cgroupStatsCommand)
+	if err != nil {
+		return CPUStats{}, MemoryStats{}, fmt.Errorf("cgroup read failed: %w", err)
+	}
+
+	lines := strings.Fields(out)
+	if len(lines) != 2 {
+		return CPUStats{}, MemoryStats{}, fmt.Errorf("unexpected cgroup output %q", out)
+	}
+
+	memUsage, err := strconv.ParseUint(lines[0], 10, 64)
+	if err != nil {
+		return CPUStats{}, MemoryStats{}, fmt.Errorf("failed to parse memory.usage_in_bytes %q: %w", lines[0], err)
+	}
+	cpuUsage, err := strconv.ParseUint(lines[1], 10, 64)
+	if err != nil {
+		return CPUStats{}, MemoryStats{}, fmt.Errorf("failed to parse cpuacct.usage %q: %w", lines[1], err)
+	}
+
+	return CPUStats{UsageCoreNanoSeconds: cpuUsage}, MemoryStats{UsageBytes: memUsage, WorkingSetBytes: memUsage}, nil
+}
@@ -0,0 +1,109 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockExecutor struct {
+	responses map[string]string
+	calls     int
+}
+
+func (m *mockExecutor) RunSSHCommand(ctx context.Context, instanceID int, cmd string) (string, error) {
+	m.calls++
+	for prefix, out := range m.responses {
+		if strings.HasPrefix(cmd, prefix) {
+			return out, nil
+		}
+	}
+	return "", fmt.Errorf("mockExecutor: no response configured for %q", cmd)
+}
+
+func TestCollector_Collect_ParsesGPUAndCgroupStats(t *testing.T) {
+	exec := &mockExecutor{responses: map[string]string{
+		"nvidia-smi":         "GPU-abc123, NVIDIA A100, 42, 1024, 40960, 250.5, 65\n",
+		"cat /sys/fs/cgroup": "2147483648 123456789\n",
+	}}
+	c := NewCollector(exec)
+
+	got, err := c.Collect(context.Background(), "pod-1", "default", "main", time.Unix(0, 0), 7)
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	if len(got.Containers) != 1 {
+		t.Fatalf("Collect() returned %d containers, want 1", len(got.Containers))
+	}
+	container := got.Containers[0]
+
+	if container.Memory.UsageBytes != 2147483648 {
+		t.Errorf("Memory.UsageBytes = %d, want 2147483648", container.Memory.UsageBytes)
+	}
+	if container.CPU.UsageCoreNanoSeconds != 123456789 {
+		t.Errorf("CPU.UsageCoreNanoSeconds = %d, want 123456789", container.CPU.UsageCoreNanoSeconds)
+	}
+
+	if len(container.Accelerators) != 1 {
+		t.Fatalf("Accelerators = %d entries, want 1", len(container.Accelerators))
+	}
+	gpu := container.Accelerators[0]
+	if gpu.UUID != "GPU-abc123" || gpu.Model != "NVIDIA A100" {
+		t.Errorf("Accelerators[0] = %+v, want UUID=GPU-abc123 Model=NVIDIA A100", gpu)
+	}
+	if gpu.DutyCycle != 42 {
+		t.Errorf("DutyCycle = %v, want 42", gpu.DutyCycle)
+	}
+	if gpu.MemoryUsedBytes != 1024*1024*1024 {
+		t.Errorf("MemoryUsedBytes = %d, want %d", gpu.MemoryUsedBytes, 1024*1024*1024)
+	}
+	if gpu.MemoryTotalBytes != 40960*1024*1024 {
+		t.Errorf("MemoryTotalBytes = %d, want %d", gpu.MemoryTotalBytes, uint64(40960*1024*1024))
+	}
+	if gpu.PowerDrawWatts != 250.5 {
+		t.Errorf("PowerDrawWatts = %v, want 250.5", gpu.PowerDrawWatts)
+	}
+	if gpu.TemperatureC != 65 {
+		t.Errorf("TemperatureC = %v, want 65", gpu.TemperatureC)
+	}
+}
+
+func TestCollector_Collect_CachesWithinTTL(t *testing.T) {
+	exec := &mockExecutor{responses: map[string]string{
+		"nvidia-smi":         "GPU-abc123, NVIDIA A100, 10, 512, 40960, 200, 60\n",
+		"cat /sys/fs/cgroup": "1000 2000\n",
+	}}
+	c := NewCollector(exec)
+
+	if _, err := c.Collect(context.Background(), "pod-1", "default", "main", time.Unix(0, 0), 7); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if _, err := c.Collect(context.Background(), "pod-1", "default", "main", time.Unix(0, 0), 7); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	if exec.calls != 2 {
+		t.Errorf("RunSSHCommand called %d times, want 2 (one per command, second Collect() served from cache)", exec.calls)
+	}
+}
+
+func TestCollector_Collect_ToleratesGPUFailure(t *testing.T) {
+	exec := &mockExecutor{responses: map[string]string{
+		"cat /sys/fs/cgroup": "1000 2000\n",
+	}}
+	c := NewCollector(exec)
+
+	got, err := c.Collect(context.Background(), "pod-1", "default", "main", time.Unix(0, 0), 7)
+	if err != nil {
+		t.Fatalf("Collect() error = %v, want nil even when GPU read fails", err)
+	}
+	if len(got.Containers[0].Accelerators) != 0 {
+		t.Errorf("Accelerators = %+v, want empty when nvidia-smi fails", got.Containers[0].Accelerators)
+	}
+	if got.Containers[0].Memory.UsageBytes != 1000 {
+		t.Errorf("Memory.UsageBytes = %d, want 1000 (cgroup read should still succeed)", got.Containers[0].Memory.UsageBytes)
+	}
+}
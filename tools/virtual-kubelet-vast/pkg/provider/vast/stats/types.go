@@ -0,0 +1,64 @@
+// Package stats collects cAdvisor-shaped CPU/memory/GPU stats for Vast.ai
+// instances over SSH, for use by VastProvider.GetStatsSummary.
+package stats
+
+import "time"
+
+// CPUStats mirrors the subset of kubelet's stats/v1alpha1 CPUStats this
+// provider can actually populate from a single cgroup read: a cumulative
+// usage counter, not an instantaneous rate (computing a rate needs two
+// samples and is left to whatever scrapes GetStatsSummary repeatedly).
+type CPUStats struct {
+	UsageCoreNanoSeconds uint64 `json:"usageCoreNanoSeconds"`
+}
+
+// MemoryStats mirrors kubelet's stats/v1alpha1 MemoryStats.
+type MemoryStats struct {
+	UsageBytes      uint64 `json:"usageBytes"`
+	WorkingSetBytes uint64 `json:"workingSetBytes"`
+}
+
+// NetworkStats is intentionally empty-valued today: Vast.ai doesn't expose
+// per-instance network counters over SSH the way cgroups expose CPU/memory,
+// so this is a placeholder kept for API shape compatibility with callers
+// that expect a NodeStats.Network field.
+type NetworkStats struct {
+	RxBytes uint64 `json:"rxBytes"`
+	TxBytes uint64 `json:"txBytes"`
+}
+
+// FsStats is also a placeholder for the same reason as NetworkStats.
+type FsStats struct {
+	UsedBytes     uint64 `json:"usedBytes"`
+	CapacityBytes uint64 `json:"capacityBytes"`
+}
+
+// AcceleratorStats reports one GPU's utilization, parsed from nvidia-smi.
+type AcceleratorStats struct {
+	UUID             string  `json:"uuid"`
+	Model            string  `json:"model"`
+	MemoryUsedBytes  uint64  `json:"memoryUsedBytes"`
+	MemoryTotalBytes uint64  `json:"memoryTotalBytes"`
+	DutyCycle        float64 `json:"dutyCycle"` // GPU utilization percent, 0-100
+	PowerDrawWatts   float64 `json:"powerDrawWatts"`
+	TemperatureC     float64 `json:"temperatureC"`
+}
+
+// ContainerStats is the per-container stats row a PodStats carries. Vast.ai
+// instances run a single managed container, so a PodStats always has exactly
+// one of these today.
+type ContainerStats struct {
+	Name         string             `json:"name"`
+	CPU          CPUStats           `json:"cpu"`
+	Memory       MemoryStats        `json:"memory"`
+	Accelerators []AcceleratorStats `json:"accelerators,omitempty"`
+}
+
+// PodStats is one pod's entry in Summary.Pods.
+type PodStats struct {
+	PodName    string           `json:"podName"`
+	Namespace  string           `json:"namespace"`
+	StartTime  time.Time        `json:"startTime"`
+	Containers []ContainerStats `json:"containers"`
+	Network    NetworkStats     `json:"network"`
+}
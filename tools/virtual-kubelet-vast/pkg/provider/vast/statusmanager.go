@@ -0,0 +1,220 @@
+package vast
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	vapi "github.com/ozzuworld/June/tools/virtual-kubelet-vast/pkg/provider/vast/api"
+)
+
+const (
+	// DefaultReconcilePollInterval is how often statusManager falls back to
+	// directly polling every tracked instance's status, as a safety net for
+	// any transition its event-stream subscriptions missed.
+	DefaultReconcilePollInterval = 5 * time.Minute
+
+	// statusUpdateBufferSize bounds how many pending notifications can queue
+	// up before a producer (an event-stream watcher or the reconcile pass)
+	// blocks waiting for NotifyPods' consumer to catch up.
+	statusUpdateBufferSize = 64
+)
+
+// trackedPod is the per-pod state statusManager keeps between refreshes.
+type trackedPod struct {
+	pod            *corev1.Pod
+	instanceID     int
+	startTime      metav1.Time
+	lastStatus     corev1.PodStatus
+	condTransition metav1.Time
+	cancel         context.CancelFunc
+}
+
+// statusManager replaces polling every tracked pod on a fixed timer with an
+// event-driven cache: each tracked instance gets a goroutine subscribed to
+// Vast.ai's event stream, and notifierFunc is only called when the newly
+// computed PodStatus actually differs from what was last reported.
+type statusManager struct {
+	client *vapi.VastClient
+
+	mu      sync.Mutex
+	tracked map[string]*trackedPod // keyed by pod.Name, matching VastProvider.instances
+
+	updates chan *corev1.Pod
+}
+
+func newStatusManager(client *vapi.VastClient) *statusManager {
+	return &statusManager{
+		client:  client,
+		tracked: make(map[string]*trackedPod),
+		updates: make(chan *corev1.Pod, statusUpdateBufferSize),
+	}
+}
+
+// Track begins watching instance's status on behalf of pod. Call it once per
+// successful CreatePod; the first-seen time becomes the pod's StartTime and
+// is preserved across every later refresh.
+func (m *statusManager) Track(ctx context.Context, pod *corev1.Pod, instance *vapi.Instance) {
+	watchCtx, cancel := context.WithCancel(ctx)
+
+	m.mu.Lock()
+	m.tracked[pod.Name] = &trackedPod{
+		pod:        pod.DeepCopy(),
+		instanceID: instance.ID,
+		startTime:  metav1.Now(),
+		cancel:     cancel,
+	}
+	m.mu.Unlock()
+
+	go m.watch(watchCtx, pod.Name, instance.ID)
+}
+
+// ContainerName returns the container name podName's tracked pod spec
+// reports, for callers (GetPod, GetStatsSummary) that only have a pod name
+// and instance, not the pod spec itself. Falls back to defaultContainerName
+// if podName isn't tracked.
+func (m *statusManager) ContainerName(podName string) string {
+	m.mu.Lock()
+	tracked, ok := m.tracked[podName]
+	m.mu.Unlock()
+
+	if !ok {
+		return defaultContainerName
+	}
+	return managedContainerName(tracked.pod)
+}
+
+// Untrack stops watching podName's instance, e.g. after DeletePod.
+func (m *statusManager) Untrack(podName string) {
+	m.mu.Lock()
+	tracked, ok := m.tracked[podName]
+	delete(m.tracked, podName)
+	m.mu.Unlock()
+
+	if ok {
+		tracked.cancel()
+	}
+}
+
+// watch subscribes to instanceID's event stream and refreshes podName's
+// cached status every time Vast.ai reports a transition. It returns once ctx
+// is cancelled (Untrack) or the subscription ends (instance destroyed),
+// leaving the periodic reconcile pass as the only remaining safety net.
+func (m *statusManager) watch(ctx context.Context, podName string, instanceID int) {
+	log := klog.FromContext(ctx).WithValues("pod", podName, "instanceId", instanceID)
+
+	events, err := m.client.WatchInstanceEvents(ctx, instanceID)
+	if err != nil {
+		log.Error(err, "failed to subscribe to instance event stream, relying on the polling fallback")
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case status, ok := <-events:
+			if !ok {
+				return
+			}
+			m.refresh(ctx, podName, status)
+		}
+	}
+}
+
+// reconcile is the polling safety net: it re-checks every tracked instance's
+// status directly, catching any transition the event stream missed.
+func (m *statusManager) reconcile(ctx context.Context) {
+	m.mu.Lock()
+	instanceIDs := make(map[string]int, len(m.tracked))
+	for podName, t := range m.tracked {
+		instanceIDs[podName] = t.instanceID
+	}
+	m.mu.Unlock()
+
+	for podName, instanceID := range instanceIDs {
+		status, err := m.client.GetInstanceStatus(ctx, instanceID)
+		if err != nil {
+			continue
+		}
+		m.refresh(ctx, podName, status)
+	}
+}
+
+// refresh recomputes podName's PodStatus from status and, only if it differs
+// from the cached one, pushes the updated pod onto the notification channel.
+func (m *statusManager) refresh(ctx context.Context, podName string, status vapi.InstanceStatus) {
+	m.mu.Lock()
+	tracked, ok := m.tracked[podName]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+
+	condStatus := convertInstanceStatusToConditionStatus(status)
+	if len(tracked.lastStatus.Conditions) == 0 || tracked.lastStatus.Conditions[0].Status != condStatus {
+		tracked.condTransition = metav1.Now()
+	}
+
+	startTime := tracked.startTime
+	newStatus := corev1.PodStatus{
+		Phase:     convertInstanceStatusToPodPhase(status),
+		StartTime: &startTime,
+		Conditions: []corev1.PodCondition{
+			{
+				Type:               corev1.PodReady,
+				Status:             condStatus,
+				LastTransitionTime: tracked.condTransition,
+			},
+		},
+		ContainerStatuses: []corev1.ContainerStatus{
+			{
+				Name:  managedContainerName(tracked.pod),
+				Ready: status == vapi.InstanceStatusRunning,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{
+						StartedAt: tracked.startTime,
+					},
+				},
+			},
+		},
+	}
+
+	if reflect.DeepEqual(newStatus, tracked.lastStatus) {
+		m.mu.Unlock()
+		return
+	}
+	tracked.lastStatus = newStatus
+	pod := tracked.pod.DeepCopy()
+	m.mu.Unlock()
+
+	pod.Status = newStatus
+
+	select {
+	case m.updates <- pod:
+	case <-ctx.Done():
+	}
+}
+
+// Run delivers cached status updates to notifierFunc as they arrive and
+// drives the periodic reconciliation pass. It blocks until ctx is cancelled.
+func (m *statusManager) Run(ctx context.Context, notifierFunc func(*corev1.Pod)) {
+	ticker := time.NewTicker(DefaultReconcilePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pod := <-m.updates:
+			notifierFunc(pod)
+		case <-ticker.C:
+			m.reconcile(ctx)
+		}
+	}
+}